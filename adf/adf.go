@@ -0,0 +1,202 @@
+package adf
+
+import (
+	"strings"
+)
+
+// Node types used in Atlassian Document Format documents,
+// see https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+const (
+	TypeDoc        = "doc"
+	TypeParagraph  = "paragraph"
+	TypeHeading    = "heading"
+	TypeBulletList = "bulletList"
+	TypeListItem   = "listItem"
+	TypeCodeBlock  = "codeBlock"
+	TypeText       = "text"
+	TypeHardBreak  = "hardBreak"
+	TypeMention    = "mention"
+	TypeInlineCard = "inlineCard"
+)
+
+// Mark types that can be applied to text nodes
+const (
+	MarkStrong = "strong"
+	MarkEm     = "em"
+	MarkCode   = "code"
+	MarkLink   = "link"
+)
+
+// Document is the root node of an Atlassian Document Format document
+type Document struct {
+	Version int     `json:"version"`
+	Type    string  `json:"type"`
+	Content []*Node `json:"content"`
+}
+
+// Node is a block or inline node in a Document
+type Node struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*Node                `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*Mark                `json:"marks,omitempty"`
+}
+
+// Mark adds formatting, like bold or a link, to a text node
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// NewDocument returns a Document with the provided block nodes as content
+func NewDocument(content ...*Node) *Document {
+	if content == nil {
+		content = []*Node{}
+	}
+
+	return &Document{Version: 1, Type: TypeDoc, Content: content}
+}
+
+// Paragraph returns a paragraph node with the provided inline nodes
+func Paragraph(content ...*Node) *Node {
+	return &Node{Type: TypeParagraph, Content: content}
+}
+
+// Heading returns a heading node, level is clamped between 1 and 6
+func Heading(level int, content ...*Node) *Node {
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+
+	return &Node{Type: TypeHeading, Attrs: map[string]interface{}{"level": level}, Content: content}
+}
+
+// BulletList returns a bullet list node, every item is wrapped in a list item when it is not one already
+func BulletList(items ...*Node) *Node {
+	list := &Node{Type: TypeBulletList}
+
+	for _, item := range items {
+		if item.Type != TypeListItem {
+			item = ListItem(item)
+		}
+		list.Content = append(list.Content, item)
+	}
+
+	return list
+}
+
+// ListItem returns a list item node with the provided block nodes
+func ListItem(content ...*Node) *Node {
+	return &Node{Type: TypeListItem, Content: content}
+}
+
+// CodeBlock returns a code block node, language may be empty
+func CodeBlock(language, code string) *Node {
+	node := &Node{Type: TypeCodeBlock}
+
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if code != "" {
+		node.Content = []*Node{Text(code)}
+	}
+
+	return node
+}
+
+// Text returns a text node with the provided marks
+func Text(text string, marks ...*Mark) *Node {
+	return &Node{Type: TypeText, Text: text, Marks: marks}
+}
+
+// Strong returns a bold text node
+func Strong(text string) *Node {
+	return Text(text, &Mark{Type: MarkStrong})
+}
+
+// Em returns an italic text node
+func Em(text string) *Node {
+	return Text(text, &Mark{Type: MarkEm})
+}
+
+// Code returns an inline code text node
+func Code(text string) *Node {
+	return Text(text, &Mark{Type: MarkCode})
+}
+
+// Link returns a text node that links to href
+func Link(text, href string) *Node {
+	return Text(text, &Mark{Type: MarkLink, Attrs: map[string]interface{}{"href": href}})
+}
+
+// HardBreak returns a line break node
+func HardBreak() *Node {
+	return &Node{Type: TypeHardBreak}
+}
+
+// Mention returns a mention node for the user with the provided id. JIRA Cloud expects the accountId, when the document
+// is converted with ToWiki for JIRA Server the id is the username. The text is shown when the user can not be resolved
+func Mention(id, text string) *Node {
+	attrs := map[string]interface{}{"id": id}
+
+	if text != "" {
+		attrs["text"] = "@" + text
+	}
+
+	return &Node{Type: TypeMention, Attrs: attrs}
+}
+
+// IssueLink returns an inline card node that renders as a link to the issue at the provided url
+func IssueLink(url string) *Node {
+	return &Node{Type: TypeInlineCard, Attrs: map[string]interface{}{"url": url}}
+}
+
+// attr returns the string value of an attribute, or an empty string when it is not set
+func (n *Node) attr(name string) string {
+	if value, ok := n.Attrs[name].(string); ok {
+		return value
+	}
+	return ""
+}
+
+// level returns the heading level of the node, JSON decoded levels are float64
+func (n *Node) level() int {
+	switch level := n.Attrs["level"].(type) {
+	case int:
+		return level
+	case float64:
+		return int(level)
+	}
+	return 1
+}
+
+// FromText converts plain text to a Document, blank lines separate paragraphs and other line breaks are kept
+func FromText(text string) *Document {
+	doc := NewDocument()
+	text = strings.Replace(text, "\r\n", "\n", -1)
+
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.Trim(block, "\n")
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		paragraph := Paragraph()
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, HardBreak())
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, Text(line))
+			}
+		}
+
+		doc.Content = append(doc.Content, paragraph)
+	}
+
+	return doc
+}
@@ -0,0 +1,54 @@
+package adf_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDocumentToJSONConversion(t *testing.T) {
+	expected := `{"version":1,"type":"doc","content":[` +
+		`{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Release 1.2.0"}]},` +
+		`{"type":"paragraph","content":[{"type":"text","text":"See "},` +
+		`{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]},` +
+		`{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[` +
+		`{"type":"mention","attrs":{"id":"5b10ac8d82e05b22cc7d4ef5","text":"@Jane"}},` +
+		`{"type":"inlineCard","attrs":{"url":"https://jira.example.com/browse/AB-1"}}]}]}]},` +
+		`{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println()"}]}]}`
+
+	document := adf.NewDocument(
+		adf.Heading(2, adf.Text("Release 1.2.0")),
+		adf.Paragraph(adf.Text("See "), adf.Link("docs", "https://example.com")),
+		adf.BulletList(adf.Paragraph(
+			adf.Mention("5b10ac8d82e05b22cc7d4ef5", "Jane"),
+			adf.IssueLink("https://jira.example.com/browse/AB-1"),
+		)),
+		adf.CodeBlock("go", "fmt.Println()"),
+	)
+	jsonBytes, err := json.Marshal(document)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(jsonBytes))
+}
+
+func TestHeadingLevelIsClamped(t *testing.T) {
+	assert.Equal(t, 1, adf.Heading(0).Attrs["level"])
+	assert.Equal(t, 6, adf.Heading(9).Attrs["level"])
+}
+
+func TestEmptyDocumentToJSONConversion(t *testing.T) {
+	jsonBytes, err := json.Marshal(adf.NewDocument())
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"version":1,"type":"doc","content":[]}`, string(jsonBytes))
+}
+
+func TestFromText(t *testing.T) {
+	expected := adf.NewDocument(
+		adf.Paragraph(adf.Text("Released in version 1.2.0."), adf.HardBreak(), adf.Text("Deployed to production.")),
+		adf.Paragraph(adf.Text("Second paragraph")),
+	)
+
+	assert.Equal(t, expected, adf.FromText("Released in version 1.2.0.\nDeployed to production.\n\nSecond paragraph\n"))
+}
@@ -0,0 +1,150 @@
+package adf
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	inlinePattern  = regexp.MustCompile("`([^`]+)`|\\*\\*([^*]+)\\*\\*|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)|\\*([^*]+)\\*|_([^_]+)_")
+)
+
+// bulletLine is a single line of a markdown bullet list
+type bulletLine struct {
+	indent int
+	text   string
+}
+
+// FromMarkdown converts a subset of Markdown to a Document. Supported are headings, paragraphs, bullet lists
+// (nested by indentation), fenced code blocks, inline code, bold, italic and links
+func FromMarkdown(markdown string) *Document {
+	doc := NewDocument()
+	lines := strings.Split(strings.Replace(markdown, "\r\n", "\n", -1), "\n")
+
+	var paragraph []string
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			doc.Content = append(doc.Content, Paragraph(parseInline(strings.Join(paragraph, " "))...))
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			language := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			doc.Content = append(doc.Content, CodeBlock(language, strings.Join(code, "\n")))
+
+		case headingPattern.MatchString(line):
+			flushParagraph()
+			match := headingPattern.FindStringSubmatch(line)
+			doc.Content = append(doc.Content, Heading(len(match[1]), parseInline(strings.TrimSpace(match[2]))...))
+
+		case bulletPattern.MatchString(line):
+			flushParagraph()
+
+			var items []bulletLine
+			for ; i < len(lines) && bulletPattern.MatchString(lines[i]); i++ {
+				match := bulletPattern.FindStringSubmatch(lines[i])
+				items = append(items, bulletLine{indent: len(match[1]), text: match[2]})
+			}
+			i--
+
+			// A list stops at a line that is indented less than its first line, the remaining lines continue
+			// the same list at their own indentation
+			list := BulletList()
+			for next := 0; next < len(items); {
+				var part *Node
+				part, next = parseBulletList(items, next)
+				list.Content = append(list.Content, part.Content...)
+			}
+			doc.Content = append(doc.Content, list)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	flushParagraph()
+	return doc
+}
+
+// parseBulletList builds a bullet list from lines starting at index start, lines with a deeper indentation than the
+// first line are added as a nested list to the previous item. It returns the list and the index of the next line
+func parseBulletList(lines []bulletLine, start int) (*Node, int) {
+	list := BulletList()
+	indent := lines[start].indent
+
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+
+		if line.indent < indent {
+			break
+		}
+
+		if line.indent > indent && len(list.Content) > 0 {
+			nested, next := parseBulletList(lines, i)
+			last := list.Content[len(list.Content)-1]
+			last.Content = append(last.Content, nested)
+			i = next
+			continue
+		}
+
+		list.Content = append(list.Content, ListItem(Paragraph(parseInline(line.text)...)))
+		i++
+	}
+
+	return list, i
+}
+
+// parseInline converts inline markdown to text nodes
+func parseInline(text string) []*Node {
+	var nodes []*Node
+	position := 0
+
+	for _, match := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] > position {
+			nodes = append(nodes, Text(text[position:match[0]]))
+		}
+
+		group := func(n int) string {
+			return text[match[2*n]:match[2*n+1]]
+		}
+
+		switch {
+		case match[2] >= 0:
+			nodes = append(nodes, Code(group(1)))
+		case match[4] >= 0:
+			nodes = append(nodes, Strong(group(2)))
+		case match[6] >= 0:
+			nodes = append(nodes, Link(group(3), group(4)))
+		case match[10] >= 0:
+			nodes = append(nodes, Em(group(5)))
+		case match[12] >= 0:
+			nodes = append(nodes, Em(group(6)))
+		}
+
+		position = match[1]
+	}
+
+	if position < len(text) {
+		nodes = append(nodes, Text(text[position:]))
+	}
+
+	return nodes
+}
@@ -0,0 +1,66 @@
+package adf_test
+
+import (
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	markdown := "# Release 1.2.0\n\n" +
+		"Released **today**, see [the notes](https://example.com) and `make release`.\n" +
+		"Second line of the *same* paragraph.\n\n" +
+		"- AB-1 Fix crash\n" +
+		"  - Nested item\n" +
+		"- AB-2 Add export\n\n" +
+		"```go\nfmt.Println()\n```\n"
+
+	expected := adf.NewDocument(
+		adf.Heading(1, adf.Text("Release 1.2.0")),
+		adf.Paragraph(
+			adf.Text("Released "),
+			adf.Strong("today"),
+			adf.Text(", see "),
+			adf.Link("the notes", "https://example.com"),
+			adf.Text(" and "),
+			adf.Code("make release"),
+			adf.Text(". Second line of the "),
+			adf.Em("same"),
+			adf.Text(" paragraph."),
+		),
+		adf.BulletList(
+			adf.ListItem(
+				adf.Paragraph(adf.Text("AB-1 Fix crash")),
+				adf.BulletList(adf.ListItem(adf.Paragraph(adf.Text("Nested item")))),
+			),
+			adf.ListItem(adf.Paragraph(adf.Text("AB-2 Add export"))),
+		),
+		adf.CodeBlock("go", "fmt.Println()"),
+	)
+
+	assert.Equal(t, expected, adf.FromMarkdown(markdown))
+}
+
+func TestFromMarkdownEmpty(t *testing.T) {
+	assert.Equal(t, adf.NewDocument(), adf.FromMarkdown(""))
+}
+
+func TestFromMarkdownFirstBulletMostIndented(t *testing.T) {
+	markdown := "    - AB-1 Fix crash\n" +
+		"  - AB-2 Add export\n" +
+		"    - Nested item\n" +
+		"- AB-3 Remove legacy API\n"
+
+	expected := adf.NewDocument(
+		adf.BulletList(
+			adf.ListItem(adf.Paragraph(adf.Text("AB-1 Fix crash"))),
+			adf.ListItem(
+				adf.Paragraph(adf.Text("AB-2 Add export")),
+				adf.BulletList(adf.ListItem(adf.Paragraph(adf.Text("Nested item")))),
+			),
+			adf.ListItem(adf.Paragraph(adf.Text("AB-3 Remove legacy API"))),
+		),
+	)
+
+	assert.Equal(t, expected, adf.FromMarkdown(markdown))
+}
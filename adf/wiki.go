@@ -0,0 +1,123 @@
+package adf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MentionFormat selects how ToWiki writes mentions, their id is an account ID or a username
+type MentionFormat int
+
+const (
+	// MentionAccountID writes [~accountid:id], for JIRA Cloud
+	MentionAccountID MentionFormat = iota
+	// MentionName writes [~id], for JIRA Server and Data Center
+	MentionName
+)
+
+// ToWiki converts the Document to JIRA wiki markup, as used by the JIRA Server and v2 REST APIs
+func ToWiki(doc *Document, mentions MentionFormat) string {
+	var blocks []string
+
+	for _, node := range doc.Content {
+		if block := blockToWiki(node, 1, mentions); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+func blockToWiki(node *Node, depth int, mentions MentionFormat) string {
+	switch node.Type {
+	case TypeParagraph:
+		return inlineToWiki(node.Content, mentions)
+	case TypeHeading:
+		return fmt.Sprintf("h%d. %s", node.level(), inlineToWiki(node.Content, mentions))
+	case TypeBulletList:
+		return listToWiki(node, depth, mentions)
+	case TypeCodeBlock:
+		language := node.attr("language")
+		if language != "" {
+			return fmt.Sprintf("{code:%s}\n%s\n{code}", language, inlineToWiki(node.Content, mentions))
+		}
+		return fmt.Sprintf("{code}\n%s\n{code}", inlineToWiki(node.Content, mentions))
+	default:
+		return inlineToWiki([]*Node{node}, mentions)
+	}
+}
+
+func listToWiki(list *Node, depth int, mentions MentionFormat) string {
+	var lines []string
+	bullet := strings.Repeat("*", depth)
+
+	for _, item := range list.Content {
+		var text []string
+		var nested []string
+
+		for _, child := range item.Content {
+			if child.Type == TypeBulletList {
+				nested = append(nested, listToWiki(child, depth+1, mentions))
+			} else {
+				text = append(text, blockToWiki(child, depth, mentions))
+			}
+		}
+
+		lines = append(lines, bullet+" "+strings.Join(text, " "))
+		lines = append(lines, nested...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func inlineToWiki(nodes []*Node, mentions MentionFormat) string {
+	var builder strings.Builder
+
+	for _, node := range nodes {
+		switch node.Type {
+		case TypeText:
+			builder.WriteString(textToWiki(node))
+		case TypeHardBreak:
+			builder.WriteString("\n")
+		case TypeMention:
+			if mentions == MentionName {
+				builder.WriteString(fmt.Sprintf("[~%s]", node.attr("id")))
+			} else {
+				builder.WriteString(fmt.Sprintf("[~accountid:%s]", node.attr("id")))
+			}
+		case TypeInlineCard:
+			builder.WriteString(fmt.Sprintf("[%s]", node.attr("url")))
+		default:
+			builder.WriteString(inlineToWiki(node.Content, mentions))
+		}
+	}
+
+	return builder.String()
+}
+
+func textToWiki(node *Node) string {
+	text := node.Text
+	var href string
+
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case MarkCode:
+			text = "{{" + text + "}}"
+		case MarkStrong:
+			text = "*" + text + "*"
+		case MarkEm:
+			text = "_" + text + "_"
+		case MarkLink:
+			if value, ok := mark.Attrs["href"].(string); ok {
+				href = value
+			}
+		}
+	}
+
+	// Links are applied last, so they wrap the other formatting
+	if href != "" {
+		text = fmt.Sprintf("[%s|%s]", text, href)
+	}
+
+	return text
+}
@@ -0,0 +1,43 @@
+package adf_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToWiki(t *testing.T) {
+	expected := "h1. Release 1.2.0\n\n" +
+		"Released *today*, see [the notes|https://example.com] and {{make release}}.\n\n" +
+		"* AB-1 Fix crash\n** Nested item\n\n* AB-2 [~accountid:5b10ac8d82e05b22cc7d4ef5]\n\n" +
+		"{code:go}\nfmt.Println()\n{code}\n\n" +
+		"[https://jira.example.com/browse/AB-1]"
+
+	document := adf.FromMarkdown("# Release 1.2.0\n\n" +
+		"Released **today**, see [the notes](https://example.com) and `make release`.\n\n" +
+		"- AB-1 Fix crash\n  - Nested item\n")
+	document.Content = append(document.Content,
+		adf.BulletList(adf.Paragraph(adf.Text("AB-2 "), adf.Mention("5b10ac8d82e05b22cc7d4ef5", "Jane"))),
+		adf.CodeBlock("go", "fmt.Println()"),
+		adf.Paragraph(adf.IssueLink("https://jira.example.com/browse/AB-1")),
+	)
+
+	assert.Equal(t, expected, adf.ToWiki(document, adf.MentionAccountID))
+}
+
+func TestToWikiMentionName(t *testing.T) {
+	document := adf.NewDocument(adf.Paragraph(adf.Text("Released by "), adf.Mention("jane", "Jane")))
+
+	assert.Equal(t, "Released by [~jane]", adf.ToWiki(document, adf.MentionName))
+}
+
+func TestToWikiDecodedDocument(t *testing.T) {
+	// Heading levels are float64 after decoding JSON
+	var document adf.Document
+	err := json.Unmarshal([]byte(`{"version":1,"type":"doc","content":[{"type":"heading","attrs":{"level":3},`+
+		`"content":[{"type":"text","text":"Title","marks":[{"type":"em"}]}]}]}`), &document)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "h3. _Title_", adf.ToWiki(&document, adf.MentionAccountID))
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"strings"
+)
+
+// auditKey is the context key of the audit entry that a client method prepares for its request
+type auditKey struct{}
+
+// SetAuditLog sets the log that every mutating request is recorded in. Every request other than GET is recorded,
+// with the details that the client method knows, like the issue and the fixVersions before and after the change
+func (c *Client) SetAuditLog(log *audit.Log) {
+	c.auditLog = log
+}
+
+// withAudit attaches the entry to the context, do completes it with the request and its outcome
+func withAudit(ctx context.Context, entry audit.Entry) context.Context {
+	return context.WithValue(ctx, auditKey{}, entry)
+}
+
+// audit records the request in the audit log when one is set and the request is a mutation. A failure to write the
+// log is reported to the output, the request itself was already sent
+func (c *Client) audit(req *http.Request, resp *http.Response, err error) {
+	if c.auditLog == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return
+	}
+
+	entry, _ := req.Context().Value(auditKey{}).(audit.Entry)
+	entry.Method = req.Method
+	entry.Endpoint = c.endpointPath(req)
+	entry.RequestID = req.Header.Get(RequestIDHeader)
+	entry.Actor = c.auditActor()
+	if entry.Operation == "" {
+		entry.Operation = req.Method + " " + entry.Endpoint
+	}
+
+	entry.Outcome = audit.OutcomeSuccess
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		if resp.StatusCode >= http.StatusBadRequest {
+			entry.Outcome = audit.OutcomeFailure
+		}
+	}
+
+	if err = c.auditLog.Record(entry); err != nil {
+		fmt.Fprintln(c.out, "Could not write audit log:", err)
+	}
+}
+
+// endpointPath returns the path of the request relative to rest/api/<version>/
+func (c *Client) endpointPath(req *http.Request) string {
+	prefix := fmt.Sprintf("/rest/api/%s/", c.apiVersion)
+	if index := strings.Index(req.URL.Path, prefix); index >= 0 {
+		return req.URL.Path[index+len(prefix):]
+	}
+	return req.URL.Path
+}
+
+// auditActor returns the actor of the log, or the user the client is authenticated as. Token clients look the user
+// up once, the log keeps it for later entries
+func (c *Client) auditActor() string {
+	if actor := c.auditLog.Actor(); actor != "" {
+		return actor
+	}
+
+	actor := c.username
+	if actor == "" {
+		user, err := c.Myself()
+		if err != nil {
+			return ""
+		}
+
+		actor = c.UserIdentifier(*user)
+	}
+
+	c.auditLog.SetActor(actor)
+	return actor
+}
+
+// fixVersionChange returns the fixVersions of the issue before and after adding and removing the names, the values
+// are unknown when the issue was passed without its fields
+func fixVersionChange(issue jira.Issue, add, remove string) (audit.Values, audit.Values) {
+	if issue.Fields == nil {
+		return nil, nil
+	}
+
+	before := make([]string, 0, len(issue.Fields.FixVersions))
+	after := make([]string, 0, len(issue.Fields.FixVersions)+1)
+	for _, version := range issue.Fields.FixVersions {
+		before = append(before, version.Name)
+		if version.Name != remove && version.Name != add {
+			after = append(after, version.Name)
+		}
+	}
+	if add != "" {
+		after = append(after, add)
+	}
+
+	return audit.Values{"fixVersions": before}, audit.Values{"fixVersions": after}
+}
+
+// statusValues returns the status of the issue, or nil when it is unknown
+func statusValues(issue jira.Issue) audit.Values {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return nil
+	}
+	return audit.Values{"status": issue.Fields.Status.Name}
+}
+
+// commentValues returns the body of the comment as text, mentions are written as they are sent to JIRA
+func (c *Client) commentValues(comment jira.Comment) audit.Values {
+	body := comment.Body
+	if body == "" && comment.Document != nil {
+		body = adf.ToWiki(comment.Document, c.mentionFormat())
+	}
+	return audit.Values{"comment": body}
+}
@@ -0,0 +1,163 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tempAuditLog(t *testing.T) (*audit.Log, func()) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	log := audit.New(filepath.Join(dir, "audit.jsonl"))
+	log.SetRunID("1337-1.2.0")
+	return log, func() { os.RemoveAll(dir) }
+}
+
+func TestClientRecordsMutations(t *testing.T) {
+	log, cleanup := tempAuditLog(t)
+	defer cleanup()
+
+	server := fakejira.New()
+	defer server.Close()
+	server.AddProject(1337, "AB")
+	server.SetTransitions(jira.Transition{ID: "31", Name: "Done", To: &jira.Status{Name: "Done"}})
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{
+		Project:     jira.Project{ID: "1337"},
+		Status:      &jira.Status{Name: "Ready for Release"},
+		FixVersions: []jira.Version{{Name: "1.1.0"}},
+	}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	client.SetAuditLog(log)
+	client.Use(api.RequestID())
+
+	assert.Nil(t, client.CreateVersion(jira.Version{Name: "1.2.0", ProjectID: 1337}))
+	assert.Nil(t, client.AddVersionToIssue(issue, jira.Version{Name: "1.2.0"}))
+	assert.Nil(t, client.AddCommentToIssue(issue, jira.Comment{Body: "Released in 1.2.0"}))
+	assert.Nil(t, client.TransitionIssue(issue, "31"))
+	_, err := client.GetIssue(issue.Key)
+	assert.Nil(t, err)
+
+	entries, err := audit.Read(log.Path(), audit.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 4)
+
+	created := entries[0]
+	assert.Equal(t, "CreateVersion", created.Operation)
+	assert.Equal(t, "POST", created.Method)
+	assert.Equal(t, "version", created.Endpoint)
+	assert.Equal(t, "1.2.0", created.Version)
+	assert.Equal(t, "username", created.Actor)
+	assert.Equal(t, "1337-1.2.0", created.RunID)
+	assert.Equal(t, audit.OutcomeSuccess, created.Outcome)
+	assert.Equal(t, http.StatusCreated, created.Status)
+	assert.Len(t, created.RequestID, 16)
+	assert.False(t, created.Time.IsZero())
+
+	assigned := entries[1]
+	assert.Equal(t, "AddVersionToIssue", assigned.Operation)
+	assert.Equal(t, "PUT", assigned.Method)
+	assert.Equal(t, "issue/"+issue.ID, assigned.Endpoint)
+	assert.Equal(t, issue.Key, assigned.Issue)
+	assert.Equal(t, []interface{}{"1.1.0"}, assigned.Before["fixVersions"])
+	assert.Equal(t, []interface{}{"1.1.0", "1.2.0"}, assigned.After["fixVersions"])
+
+	assert.Equal(t, "AddCommentToIssue", entries[2].Operation)
+	assert.Equal(t, "Released in 1.2.0", entries[2].After["comment"])
+
+	assert.Equal(t, "TransitionIssue", entries[3].Operation)
+	assert.Equal(t, "Ready for Release", entries[3].Before["status"])
+	assert.Equal(t, "31", entries[3].After["transition"])
+}
+
+func TestClientRecordsFailedMutations(t *testing.T) {
+	log, cleanup := tempAuditLog(t)
+	defer cleanup()
+
+	server := fakejira.New()
+	defer server.Close()
+	version := server.AddVersion(jira.Version{Name: "1.2.0", ProjectID: 1337})
+	server.FailNext("PUT", "version/"+version.ID, http.StatusForbidden)
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	client.SetAuditLog(log)
+
+	assert.NotNil(t, client.ReleaseVersion(version))
+
+	entries, err := audit.Read(log.Path(), audit.Filter{Failed: true})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "ReleaseVersion", entries[0].Operation)
+	assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	assert.Equal(t, http.StatusForbidden, entries[0].Status)
+	assert.Equal(t, false, entries[0].Before["released"])
+	assert.Equal(t, true, entries[0].After["released"])
+}
+
+func TestTokenClientRecordsAuthenticatedUser(t *testing.T) {
+	log, cleanup := tempAuditLog(t)
+	defer cleanup()
+
+	server := fakejira.New()
+	defer server.Close()
+	server.RequireToken("secret")
+	server.SetMyself(jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "Release Bot"})
+	server.AddProject(1337, "AB")
+	server.AddVersion(jira.Version{Name: "1.2.0", ProjectID: 1337})
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+
+	client, _ := api.NewTokenClient(server.URL, "secret")
+	client.SetOutput(ioutil.Discard)
+	client.SetAuditLog(log)
+
+	assert.Nil(t, client.AddVersionToIssue(jira.Issue{ID: issue.ID, Key: issue.Key}, jira.Version{Name: "1.2.0"}))
+	assert.Nil(t, client.RemoveVersionFromIssue(jira.Issue{ID: issue.ID, Key: issue.Key}, jira.Version{Name: "1.2.0"}))
+
+	entries, err := audit.Read(log.Path(), audit.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", entry.Actor)
+		// The issue was passed without its fields, so its fixVersions are unknown
+		assert.Nil(t, entry.Before)
+		assert.Nil(t, entry.After)
+	}
+	// The user is looked up once
+	assert.Equal(t, []string{"PUT issue/" + issue.ID, "GET myself", "PUT issue/" + issue.ID}, server.Requests())
+}
+
+func TestClientRecordsDeletedCommentBody(t *testing.T) {
+	log, cleanup := tempAuditLog(t)
+	defer cleanup()
+
+	server := fakejira.New()
+	defer server.Close()
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	client.SetAuditLog(log)
+
+	comment, err := client.CreateComment(issue, jira.Comment{Body: "Released in 1.2.0"})
+	assert.Nil(t, err)
+	assert.Nil(t, client.DeleteComment(issue, comment.ID))
+
+	entries, err := audit.Read(log.Path(), audit.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	deleted := entries[1]
+	assert.Equal(t, "DeleteComment", deleted.Operation)
+	assert.Equal(t, "Released in 1.2.0", deleted.Before["comment"])
+	assert.Nil(t, deleted.After)
+}
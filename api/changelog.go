@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// changelogResult represents the response from the changelog requests
+type changelogResult struct {
+	StartAt    int                   `json:"startAt"`
+	MaxResults int                   `json:"maxResults"`
+	Total      int                   `json:"total"`
+	IsLast     bool                  `json:"isLast"`
+	Values     []jira.ChangelogEntry `json:"values"`
+}
+
+// expandedChangelog represents the response from the issue request with the changelog expanded
+type expandedChangelog struct {
+	Changelog struct {
+		Histories []jira.ChangelogEntry `json:"histories"`
+	} `json:"changelog"`
+}
+
+// GetIssueChangelog returns all changelog entries of the JIRA issue, all pages are fetched. JIRA Server and Data
+// Center do not have the changelog endpoint, for them the changelog is read from the issue instead.
+// ErrIssueNotFound is returned when the issue does not exist
+func (c *Client) GetIssueChangelog(issue jira.Issue) (_ []jira.ChangelogEntry, err error) {
+	ctx, span := c.startSpan("GetIssueChangelog", AttributeIssueKey.String(issue.Key))
+	defer func() { endSpan(span, err) }()
+
+	idOrKey := issue.ID
+	if idOrKey == "" {
+		idOrKey = issue.Key
+	}
+
+	var changelog []jira.ChangelogEntry
+
+	for {
+		result, status, err := c.changelogPage(ctx, idOrKey, len(changelog))
+
+		if status == http.StatusNotFound && len(changelog) == 0 {
+			return c.expandedChangelog(ctx, idOrKey)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		changelog = append(changelog, result.Values...)
+
+		if result.IsLast || len(result.Values) == 0 || len(changelog) >= result.Total {
+			break
+		}
+	}
+
+	return changelog, nil
+}
+
+// changelogPage returns a single page of changelog entries starting at the provided index, and the status code
+func (c *Client) changelogPage(ctx context.Context, idOrKey string, startAt int) (*changelogResult, int, error) {
+	query := url.Values{}
+	query.Set("startAt", strconv.Itoa(startAt))
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("issue/%s/changelog", url.PathEscape(idOrKey)), query, nil)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, resp.StatusCode, newStatusError("GetIssueChangelog", resp.StatusCode, "")
+	}
+
+	var result changelogResult
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return &result, resp.StatusCode, nil
+}
+
+// expandedChangelog returns the changelog that JIRA Server and Data Center include in the issue when it is expanded
+func (c *Client) expandedChangelog(ctx context.Context, idOrKey string) ([]jira.ChangelogEntry, error) {
+	query := url.Values{}
+	query.Set("expand", "changelog")
+	query.Set("fields", "none")
+
+	req, err := c.newRequest(ctx, "GET", "issue/"+url.PathEscape(idOrKey), query, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrIssueNotFound
+		}
+
+		return nil, newStatusError("GetIssueChangelog", resp.StatusCode, "")
+	}
+
+	var result expandedChangelog
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Changelog.Histories, nil
+}
+
+// FixVersionHistory returns the history of the Fix Version field of the issue from its changelog. Use Added on the
+// history to find out who set a fixVersion and when
+func FixVersionHistory(reader ChangelogReader, issue jira.Issue) (jira.FixVersionHistory, error) {
+	changelog, err := reader.GetIssueChangelog(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return jira.NewFixVersionHistory(changelog), nil
+}
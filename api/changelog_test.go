@@ -0,0 +1,128 @@
+package api_test
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const changelogEntry = `{
+	"id": "%v",
+	"author": {"name": "jdoe"},
+	"created": "2026-10-18T09:30:00.000+0000",
+	"items": [{"field": "Fix Version", "fieldtype": "jira", "fieldId": "fixVersions", "to": "10000", "toString": "1.2.0"}]
+}`
+
+func TestGetIssueChangelogFetchesAllPages(t *testing.T) {
+	var queries []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.RawQuery)
+		if req.URL.Query().Get("startAt") == "0" {
+			fmt.Fprintf(writer, `{"startAt":0,"maxResults":1,"total":2,"isLast":false,"values":[%v]}`, fmt.Sprintf(changelogEntry, 1))
+			return
+		}
+		fmt.Fprintf(writer, `{"startAt":1,"maxResults":1,"total":2,"isLast":true,"values":[%v]}`, fmt.Sprintf(changelogEntry, 2))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	changelog, err := client.GetIssueChangelog(jira.Issue{ID: "10001", Key: "AB-1"})
+
+	assert.Nil(t, err)
+	assert.Len(t, changelog, 2)
+	assert.Equal(t, "1", changelog[0].ID)
+	assert.Equal(t, "2", changelog[1].ID)
+	assert.Equal(t, "jdoe", changelog[0].Author.Name)
+	assert.Equal(t, "1.2.0", changelog[0].Items[0].ToString)
+	assert.Equal(t, []string{"startAt=0", "startAt=1"}, queries)
+}
+
+func TestGetIssueChangelogFallsBackToExpandedIssue(t *testing.T) {
+	var paths []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path+"?"+req.URL.RawQuery)
+		if req.URL.Path == "/rest/api/latest/issue/AB-1/changelog" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(writer, `{"key":"AB-1","changelog":{"startAt":0,"maxResults":1,"total":1,"histories":[%v]}}`, fmt.Sprintf(changelogEntry, 1))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	changelog, err := client.GetIssueChangelog(jira.Issue{Key: "AB-1"})
+
+	assert.Nil(t, err)
+	assert.Len(t, changelog, 1)
+	assert.Equal(t, "1.2.0", changelog[0].Items[0].ToString)
+	assert.Equal(t, []string{
+		"/rest/api/latest/issue/AB-1/changelog?startAt=0",
+		"/rest/api/latest/issue/AB-1?expand=changelog&fields=none",
+	}, paths)
+}
+
+func TestGetIssueChangelogUnknownIssue(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	_, err := server.Client().GetIssueChangelog(jira.Issue{Key: "AB-1"})
+
+	assert.Equal(t, api.ErrIssueNotFound, err)
+}
+
+func TestGetIssueChangelogReturnsStatusError(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusForbidden)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	_, err := client.GetIssueChangelog(jira.Issue{ID: "10001"})
+
+	assert.EqualError(t, err, "GetIssueChangelog response status is 403")
+	assert.True(t, api.IsAuthError(err))
+}
+
+func TestFixVersionHistory(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+	server.AddProject(1337, "AB")
+	server.SetMyself(jira.User{Name: "release-bot"})
+	manual := server.AddVersion(jira.Version{Name: "1.1.0", ProjectID: 1337})
+	ours := server.AddVersion(jira.Version{Name: "1.2.0", ProjectID: 1337})
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}, FixVersions: []jira.Version{manual}}})
+	server.AddChangelog(issue.Key, jira.ChangelogEntry{
+		Author:  &jira.User{Name: "jdoe"},
+		Created: "2026-10-18T09:30:00.000+0000",
+		Items:   []jira.ChangelogItem{{Field: "Fix Version", To: manual.ID, ToString: manual.Name}},
+	})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	assert.Nil(t, client.AddVersionToIssue(issue, ours))
+
+	history, err := api.FixVersionHistory(client, issue)
+
+	assert.Nil(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "jdoe", history.Added("1.1.0").Author.Name)
+	assert.Equal(t, "release-bot", history.Added("1.2.0").Author.Name)
+	assert.Equal(t, ours.ID, history.Added("1.2.0").VersionID)
+}
@@ -1,23 +1,40 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/credentials"
 	"github.com/marcelblijleven/version-meister/jira"
+	"go.opentelemetry.io/otel/trace"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"time"
 )
 
+// ErrIssueNotFound is returned when a requested issue does not exist or is not visible to the user
+var ErrIssueNotFound = errors.New("Issue does not exist")
+
 // Client is the JIRA api client
 type Client struct {
 	baseURL    *url.URL
 	username   string
 	password   string
+	token      string
+	apiVersion APIVersion
 	httpClient *http.Client
+	middleware []Middleware
+	out        io.Writer
+	// ctx is the context of the requests, see WithContext
+	ctx            context.Context
+	tracerProvider trace.TracerProvider
+	auditLog       *audit.Log
 }
 
 type errorMessage struct {
@@ -31,7 +48,10 @@ type innerErrorMessage struct {
 
 // JQLResult represents the response from the ?search requests
 type jqlResult struct {
-	Issues []jira.Issue `json:"issues,omitempty"`
+	StartAt    int          `json:"startAt"`
+	MaxResults int          `json:"maxResults"`
+	Total      int          `json:"total"`
+	Issues     []jira.Issue `json:"issues,omitempty"`
 }
 
 // UpdateHelper allows for easy marshalling of update data
@@ -46,7 +66,9 @@ type fixVersionHelper struct {
 
 // SetContainer allows for easy marshalling of update data
 type setContainer struct {
-	Sets []updateSet `json:"set,omitempty"`
+	Sets   []updateSet `json:"set,omitempty"`
+	Add    *updateSet  `json:"add,omitempty"`
+	Remove *updateSet  `json:"remove,omitempty"`
 }
 
 // UpdateSet allows for easy marshalling of update data
@@ -59,6 +81,11 @@ func (c *Client) SetHTTPClient(httpClient *http.Client) {
 	c.httpClient = httpClient
 }
 
+// SetOutput sets the writer that progress messages are written to, os.Stdout is used by default
+func (c *Client) SetOutput(out io.Writer) {
+	c.out = out
+}
+
 // NewClient returns a client base on the provided values
 func NewClient(baseURL, username, password string) (*Client, error) {
 	if baseURL == "" {
@@ -80,174 +107,332 @@ func NewClient(baseURL, username, password string) (*Client, error) {
 		baseURL:    parsedURL,
 		username:   username,
 		password:   password,
+		apiVersion: APIVersionLatest,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
+		out:        os.Stdout,
 	}
 
 	return &client, nil
 }
 
-// Search returns a slice of JIRA issues that match the provided JQL query
-func (c *Client) Search(jql string) ([]jira.Issue, error) {
-	// TODO: handle maxResults, startAt
-	query := url.QueryEscape(jql)
-	endpoint := fmt.Sprintf("rest/api/latest/search?jql=%s", query)
-	endpointURL, err := url.Parse(endpoint)
+// NewTokenClient returns a client that authenticates with a personal access token, as used by JIRA Server and
+// Data Center. For JIRA Cloud API tokens use NewClient with the email address as username and the token as password
+func NewTokenClient(baseURL, token string) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.New("Base url cannot be empty")
+	}
+	if token == "" {
+		return nil, errors.New("Token cannot be empty")
+	}
 
+	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	resolvedURL := c.baseURL.ResolveReference(endpointURL)
-	req, err := http.NewRequest("GET", resolvedURL.String(), nil)
+	client := Client{
+		baseURL:    parsedURL,
+		token:      token,
+		apiVersion: APIVersionLatest,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		out:        os.Stdout,
+	}
+
+	return &client, nil
+}
+
+// NewClientWithProvider returns a client that uses the credentials that the provider returns for the host of the
+// base url. Credentials with only a token use bearer authentication, see NewTokenClient
+func NewClientWithProvider(baseURL string, provider credentials.Provider) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("Credentials provider cannot be nil")
+	}
 
+	host, err := credentials.Host(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(c.username, c.password)
-	resp, err := c.httpClient.Do(req)
-
+	creds, err := provider.Credentials(host)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Could not get credentials for %v: %w", host, err)
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
+	if creds.Username == "" && creds.Token != "" {
+		return NewTokenClient(baseURL, creds.Token)
+	}
+
+	password := creds.Password
+	if password == "" {
+		// JIRA Cloud API tokens are used as password
+		password = creds.Token
+	}
+
+	return NewClient(baseURL, creds.Username, password)
+}
+
+// Search returns a slice of JIRA issues that match the provided JQL query.
+// All result pages are fetched, so the slice contains every matching issue
+func (c *Client) Search(jql string) (_ []jira.Issue, err error) {
+	ctx, span := c.startSpan("Search", AttributeJQL.String(jql))
+	defer func() { endSpan(span, err) }()
+
+	var issues []jira.Issue
+
+	for {
+		result, err := c.searchPage(ctx, jql, len(issues))
 
 		if err != nil {
 			return nil, err
 		}
 
-		var result jqlResult
-		if err = json.Unmarshal(body, &result); err != nil {
-			return nil, err
-		}
+		issues = append(issues, result.Issues...)
 
-		return result.Issues, nil
+		if len(result.Issues) == 0 || len(issues) >= result.Total {
+			break
+		}
 	}
 
-	return nil, fmt.Errorf("Search response status is %v", resp.StatusCode)
+	return issues, nil
 }
 
-// CreateVersion creates a new JIRA fixVersion based on the provided Version
-func (c *Client) CreateVersion(version jira.Version) error {
-	endpoint := "rest/api/latest/version"
-	endpointURL, err := url.Parse(endpoint)
+// searchPage returns a single page of search results, starting at the provided index
+func (c *Client) searchPage(ctx context.Context, jql string, startAt int) (*jqlResult, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("startAt", strconv.Itoa(startAt))
+
+	req, err := c.newRequest(ctx, "GET", "search", query, nil)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resolvedURL := c.baseURL.ResolveReference(endpointURL)
+	resp, err := c.do(req)
 
-	buffer := new(bytes.Buffer)
-	if json.NewEncoder(buffer).Encode(version); err != nil {
-		return err
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", resolvedURL.String(), buffer)
+	if resp.StatusCode == http.StatusOK {
+		var result jqlResult
+		if err = decodeResponse(resp, &result); err != nil {
+			return nil, err
+		}
+
+		return &result, nil
+	}
+
+	resp.Body.Close()
+	return nil, newStatusError("Search", resp.StatusCode, "")
+}
+
+// CreateVersion creates a new JIRA fixVersion based on the provided Version
+func (c *Client) CreateVersion(version jira.Version) (err error) {
+	ctx, span := c.startSpan("CreateVersion", AttributeVersion.String(version.Name))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "CreateVersion",
+		Version:   version.Name,
+		After: audit.Values{
+			"name":        version.Name,
+			"projectId":   version.ProjectID,
+			"released":    version.Released,
+			"releaseDate": version.ReleaseDate,
+		},
+	})
+	req, err := c.newRequest(ctx, "POST", "version", nil, version)
 
 	if err != nil {
 		return err
 	}
 
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		if resp.StatusCode != http.StatusCreated {
-			msg, err := handleErrorMessage(resp)
-
-			if err != nil {
-				return fmt.Errorf("CreateVersion response status is %v", resp.StatusCode)
-			}
+		msg, err := handleErrorMessage(resp)
 
-			if msg.Errors.Name == "A version with this name already exists in this project." {
-				fmt.Println(msg.Errors.Name, "Using existing version")
-				return nil
-			}
+		if err != nil {
+			return newStatusError("CreateVersion", resp.StatusCode, "")
+		}
 
-			return fmt.Errorf(msg.Errors.Name)
+		if msg.Errors.Name == "A version with this name already exists in this project." {
+			fmt.Fprintln(c.out, msg.Errors.Name, "Using existing version")
+			return nil
 		}
 
+		return newStatusError("CreateVersion", resp.StatusCode, msg.Errors.Name)
 	}
 
-	fmt.Println("Successfully created version", version.Name)
+	resp.Body.Close()
+	fmt.Fprintln(c.out, "Successfully created version", version.Name)
 	return nil
 }
 
-// AddVersionToIssue adds the provided JIRA version to the provided JIRA issue as a fixVersion
-func (c *Client) AddVersionToIssue(issue jira.Issue, version jira.Version) error {
-	endpoint, err := url.Parse(fmt.Sprintf("rest/api/latest/issue/%s", issue.ID))
+// GetIssue returns the JIRA issue with the provided key or ID, ErrIssueNotFound is returned when it does not exist
+func (c *Client) GetIssue(key string) (_ *jira.Issue, err error) {
+	ctx, span := c.startSpan("GetIssue", AttributeIssueKey.String(key))
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", "issue/"+url.PathEscape(key), nil, nil)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	resolvedURL := c.baseURL.ResolveReference(endpoint)
-	container := setContainer{
-		Sets: []updateSet{updateSet{Name: version.Name}},
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
 	}
-	update := updateHelper{FixVersion: fixVersionHelper{
-		SetContainers: []setContainer{container},
-	}}
 
-	buffer := new(bytes.Buffer)
-	if json.NewEncoder(buffer).Encode(update); err != nil {
-		return err
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrIssueNotFound
+		}
+
+		return nil, newStatusError("GetIssue", resp.StatusCode, "")
 	}
 
-	req, err := http.NewRequest("PUT", resolvedURL.String(), buffer)
+	var issue jira.Issue
+	if err = decodeResponse(resp, &issue); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+// Myself returns the user that the client is authenticated as
+func (c *Client) Myself() (_ *jira.User, err error) {
+	ctx, span := c.startSpan("Myself")
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", "myself", nil, nil)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 
 	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError("Myself", resp.StatusCode, "")
+	}
+
+	var user jira.User
+	if err = decodeResponse(resp, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// AddVersionToIssue adds the provided JIRA version to the provided JIRA issue as a fixVersion
+func (c *Client) AddVersionToIssue(issue jira.Issue, version jira.Version) (err error) {
+	ctx, span := c.startSpan("AddVersionToIssue", AttributeIssueKey.String(issue.Key), AttributeVersion.String(version.Name))
+	defer func() { endSpan(span, err) }()
+
+	// Use add instead of set, so existing fixVersions on the issue are kept
+	container := setContainer{
+		Add: &updateSet{Name: version.Name},
+	}
+
+	before, after := fixVersionChange(issue, version.Name, "")
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "AddVersionToIssue",
+		Issue:     issue.Key,
+		Version:   version.Name,
+		Before:    before,
+		After:     after,
+	})
+
+	if err := c.updateFixVersions(ctx, "AddVersion", issue, container); err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("AddVersion response status is %v", resp.StatusCode)
+	fmt.Fprintf(c.out, "Successfully added version %v to issue %v\n", version.Name, issue.Key)
+	return nil
+}
+
+// RemoveVersionFromIssue removes the provided JIRA version from the fixVersions of the provided JIRA issue
+func (c *Client) RemoveVersionFromIssue(issue jira.Issue, version jira.Version) (err error) {
+	ctx, span := c.startSpan("RemoveVersionFromIssue", AttributeIssueKey.String(issue.Key), AttributeVersion.String(version.Name))
+	defer func() { endSpan(span, err) }()
+
+	container := setContainer{
+		Remove: &updateSet{Name: version.Name},
+	}
+
+	before, after := fixVersionChange(issue, "", version.Name)
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "RemoveVersionFromIssue",
+		Issue:     issue.Key,
+		Version:   version.Name,
+		Before:    before,
+		After:     after,
+	})
+
+	if err := c.updateFixVersions(ctx, "RemoveVersion", issue, container); err != nil {
+		return err
 	}
 
-	fmt.Printf("Successfully added version %v to issue %v\n", version.Name, issue.Key)
+	fmt.Fprintf(c.out, "Successfully removed version %v from issue %v\n", version.Name, issue.Key)
 	return nil
 }
 
-// AddCommentToIssue adds the provided JIRA comment as a user comment on the provided JIRA issue
-func (c *Client) AddCommentToIssue(issue jira.Issue, comment jira.Comment) error {
-	endpoint, err := url.Parse(fmt.Sprintf("rest/api/latest/issue/%s/comment", issue.ID))
+// updateFixVersions applies the update operation to the fixVersions of the issue, name is used in error messages
+func (c *Client) updateFixVersions(ctx context.Context, name string, issue jira.Issue, container setContainer) error {
+	update := updateHelper{FixVersion: fixVersionHelper{
+		SetContainers: []setContainer{container},
+	}}
+
+	req, err := c.newRequest(ctx, "PUT", "issue/"+issue.ID, nil, update)
 
 	if err != nil {
 		return err
 	}
 
-	resolvedURL := c.baseURL.ResolveReference(endpoint)
-	buffer := new(bytes.Buffer)
-	if json.NewEncoder(buffer).Encode(comment); err != nil {
+	resp, err := c.do(req)
+
+	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", resolvedURL.String(), buffer)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newStatusError(name, resp.StatusCode, "")
+	}
+
+	return nil
+}
+
+// AddCommentToIssue adds the provided JIRA comment as a user comment on the provided JIRA issue.
+// The comment body is converted to the format that the selected API version expects
+func (c *Client) AddCommentToIssue(issue jira.Issue, comment jira.Comment) (err error) {
+	ctx, span := c.startSpan("AddCommentToIssue", AttributeIssueKey.String(issue.Key))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withAudit(ctx, audit.Entry{Operation: "AddCommentToIssue", Issue: issue.Key, After: c.commentValues(comment)})
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("issue/%s/comment", issue.ID), nil, c.commentPayload(comment))
 
 	if err != nil {
 		return err
 	}
 
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 
 	if err != nil {
 		return err
@@ -257,12 +442,13 @@ func (c *Client) AddCommentToIssue(issue jira.Issue, comment jira.Comment) error
 		msg, err := handleErrorMessage(resp)
 
 		if err != nil {
-			return fmt.Errorf("AddComment response status is %v", resp.StatusCode)
+			return newStatusError("AddComment", resp.StatusCode, "")
 		}
 
-		return fmt.Errorf(msg.Errors.Name)
+		return newStatusError("AddComment", resp.StatusCode, msg.Errors.Name)
 	}
 
+	resp.Body.Close()
 	return nil
 }
 
@@ -1,16 +1,30 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
 	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/credentials"
 	"github.com/marcelblijleven/version-meister/jira"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 )
 
+// staticProvider returns fixed credentials per host
+type staticProvider map[string]credentials.Credentials
+
+func (p staticProvider) Credentials(host string) (*credentials.Credentials, error) {
+	creds, ok := p[host]
+	if !ok {
+		return nil, credentials.ErrNotFound
+	}
+	return &creds, nil
+}
+
 func testHTTPClient(handler http.Handler) (*http.Client, func()) {
 	server := httptest.NewServer(handler)
 
@@ -121,6 +135,88 @@ func TestClientSearch(t *testing.T) {
 	assert.Equal(t, 1, len(issues))
 }
 
+func TestTokenClientUsesBearerAuth(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		_, _, ok := req.BasicAuth()
+		assert.False(t, ok)
+		assert.Equal(t, "Bearer secret-token", req.Header.Get("Authorization"))
+		writer.Write([]byte(searchResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, err := api.NewTokenClient("http://fake.com", "secret-token")
+	assert.Nil(t, err)
+	client.SetHTTPClient(httpClient)
+
+	issues, err := client.Search("fixVersion IS EMPTY")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(issues))
+}
+
+func TestNewTokenClientEmptyTokenReturnsError(t *testing.T) {
+	client, err := api.NewTokenClient("http://fake.com", "")
+
+	assert.Equal(t, "Token cannot be empty", err.Error())
+	assert.Nil(t, client)
+}
+
+func TestNewClientWithProvider(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "me@example.com", username)
+		assert.Equal(t, "api-token", password)
+		writer.Write([]byte(searchResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	provider := credentials.Chain{staticProvider{
+		"fake.com": {Username: "me@example.com", Token: "api-token"},
+	}}
+	client, err := api.NewClientWithProvider("http://fake.com", provider)
+	assert.Nil(t, err)
+	client.SetHTTPClient(httpClient)
+
+	issues, err := client.Search("fixVersion IS EMPTY")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(issues))
+}
+
+func TestNewClientWithProviderWithoutCredentialsReturnsError(t *testing.T) {
+	client, err := api.NewClientWithProvider("http://fake.com", credentials.Chain{})
+
+	assert.Equal(t, "Could not get credentials for fake.com: No credentials found", err.Error())
+	assert.Nil(t, client)
+}
+
+func TestClientSearchFetchesAllPages(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		startAt := req.URL.Query().Get("startAt")
+		assert.Equal(t, "project = AB", req.URL.Query().Get("jql"))
+
+		if startAt == "0" {
+			writer.Write([]byte(`{"startAt":0,"maxResults":1,"total":2,"issues":[{"id":"1","key":"AB-1"}]}`))
+			return
+		}
+
+		assert.Equal(t, "1", startAt)
+		writer.Write([]byte(`{"startAt":1,"maxResults":1,"total":2,"issues":[{"id":"2","key":"AB-2"}]}`))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	issues, err := client.Search("project = AB")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(issues))
+	assert.Equal(t, "AB-1", issues[0].Key)
+	assert.Equal(t, "AB-2", issues[1].Key)
+}
+
 func TestCreateVersion(t *testing.T) {
 	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
 		username, password, ok := req.BasicAuth()
@@ -148,6 +244,27 @@ func TestCreateVersion(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestSetOutput(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusCreated) // Set the status code to 201 - Created
+		writer.Write([]byte(createVersionResponse))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	buffer := new(bytes.Buffer)
+	client.SetOutput(buffer)
+
+	err := client.CreateVersion(jira.Version{Name: "Test-version", ProjectID: 1337})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Successfully created version Test-version\n", buffer.String())
+}
+
 func TestCreateVersionExistingVersionDoesNotReturnError(t *testing.T) {
 	errorResponse := `
 	{
@@ -252,6 +369,87 @@ func TestAddVersionToIssue(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAddVersionToIssueKeepsExistingVersions(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1", req.URL.Path)
+		assert.JSONEq(t, `{"update":{"fixVersions":[{"add":{"name":"Test-version"}}]}}`, string(body))
+		writer.WriteHeader(http.StatusNoContent) // Set the status code to 204 - No content
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.AddVersionToIssue(jira.Issue{ID: "1", Key: "AB-124"}, jira.Version{Name: "Test-version"})
+
+	assert.Nil(t, err)
+}
+
+func TestRemoveVersionFromIssue(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1", req.URL.Path)
+		assert.JSONEq(t, `{"update":{"fixVersions":[{"remove":{"name":"Test-version"}}]}}`, string(body))
+		writer.WriteHeader(http.StatusNoContent) // Set the status code to 204 - No content
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.RemoveVersionFromIssue(jira.Issue{ID: "1", Key: "AB-124"}, jira.Version{Name: "Test-version"})
+
+	assert.Nil(t, err)
+}
+
+func TestGetIssue(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "username", username)
+		assert.Equal(t, "password", password)
+		assert.Equal(t, "/rest/api/latest/issue/AB-123", req.URL.Path)
+		writer.Write([]byte(`{"id":"1337","key":"AB-123","fields":{"summary":"A fine test issue"}}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	issue, err := client.GetIssue("AB-123")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1337", issue.ID)
+	assert.Equal(t, "A fine test issue", issue.Fields.Summary)
+}
+
+func TestGetIssueNotFound(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusNotFound) // Set the status code to 404 - Not found
+		writer.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	issue, err := client.GetIssue("AB-404")
+
+	assert.Equal(t, api.ErrIssueNotFound, err)
+	assert.Nil(t, issue)
+}
+
 func TestAddCommentToIssue(t *testing.T) {
 	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
 		username, password, ok := req.BasicAuth()
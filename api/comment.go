@@ -0,0 +1,201 @@
+package api
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// commentsResult represents the response from the comment list requests
+type commentsResult struct {
+	StartAt    int            `json:"startAt"`
+	MaxResults int            `json:"maxResults"`
+	Total      int            `json:"total"`
+	Comments   []jira.Comment `json:"comments"`
+}
+
+// ListComments returns all comments on the provided JIRA issue
+func (c *Client) ListComments(issue jira.Issue) (_ []jira.Comment, err error) {
+	ctx, span := c.startSpan("ListComments", AttributeIssueKey.String(issue.Key))
+	defer func() { endSpan(span, err) }()
+
+	var comments []jira.Comment
+
+	for {
+		query := url.Values{}
+		query.Set("startAt", strconv.Itoa(len(comments)))
+
+		req, err := c.newRequest(ctx, "GET", fmt.Sprintf("issue/%s/comment", issue.ID), query, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.do(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, newStatusError("ListComments", resp.StatusCode, "")
+		}
+
+		var result commentsResult
+		if err = decodeResponse(resp, &result); err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, result.Comments...)
+
+		if len(result.Comments) == 0 || len(comments) >= result.Total {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// GetComment returns the comment with the provided ID on the provided JIRA issue
+func (c *Client) GetComment(issue jira.Issue, commentID string) (_ *jira.Comment, err error) {
+	ctx, span := c.startSpan("GetComment", AttributeIssueKey.String(issue.Key), AttributeComment.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("issue/%s/comment/%s", issue.ID, commentID), nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError("GetComment", resp.StatusCode, "")
+	}
+
+	var comment jira.Comment
+	if err = decodeResponse(resp, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// CreateComment adds the comment to the JIRA issue like AddCommentToIssue, and returns the created comment
+// including its ID
+func (c *Client) CreateComment(issue jira.Issue, comment jira.Comment) (_ *jira.Comment, err error) {
+	ctx, span := c.startSpan("CreateComment", AttributeIssueKey.String(issue.Key))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withAudit(ctx, audit.Entry{Operation: "CreateComment", Issue: issue.Key, After: c.commentValues(comment)})
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("issue/%s/comment", issue.ID), nil, c.commentPayload(comment))
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		msg, err := handleErrorMessage(resp)
+
+		if err != nil || msg.Errors.Name == "" {
+			return nil, newStatusError("CreateComment", resp.StatusCode, "")
+		}
+
+		return nil, newStatusError("CreateComment", resp.StatusCode, msg.Errors.Name)
+	}
+
+	var created jira.Comment
+	if err = decodeResponse(resp, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateComment replaces the body and visibility of an existing comment, the comment ID must be set
+func (c *Client) UpdateComment(issue jira.Issue, comment jira.Comment) (err error) {
+	ctx, span := c.startSpan("UpdateComment", AttributeIssueKey.String(issue.Key), AttributeComment.String(comment.ID))
+	defer func() { endSpan(span, err) }()
+
+	if comment.ID == "" {
+		return fmt.Errorf("Comment ID cannot be empty")
+	}
+
+	path := fmt.Sprintf("issue/%s/comment/%s", issue.ID, comment.ID)
+	ctx = withAudit(ctx, audit.Entry{Operation: "UpdateComment", Issue: issue.Key, After: c.commentValues(comment)})
+	req, err := c.newRequest(ctx, "PUT", path, nil, c.commentPayload(comment))
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		msg, err := handleErrorMessage(resp)
+
+		if err != nil || msg.Errors.Name == "" {
+			return newStatusError("UpdateComment", resp.StatusCode, "")
+		}
+
+		return newStatusError("UpdateComment", resp.StatusCode, msg.Errors.Name)
+	}
+
+	resp.Body.Close()
+	return nil
+}
+
+// DeleteComment deletes the comment with the provided ID from the provided JIRA issue
+func (c *Client) DeleteComment(issue jira.Issue, commentID string) (err error) {
+	ctx, span := c.startSpan("DeleteComment", AttributeIssueKey.String(issue.Key), AttributeComment.String(commentID))
+	defer func() { endSpan(span, err) }()
+
+	entry := audit.Entry{Operation: "DeleteComment", Issue: issue.Key}
+	if c.auditLog != nil {
+		// The body is gone after the delete, so it is looked up for the audit log first. When the lookup fails the
+		// delete fails too, and is recorded without the body
+		if existing, err := c.WithContext(ctx).GetComment(issue, commentID); err == nil {
+			entry.Before = c.commentValues(*existing)
+		}
+	}
+
+	ctx = withAudit(ctx, entry)
+	req, err := c.newRequest(ctx, "DELETE", fmt.Sprintf("issue/%s/comment/%s", issue.ID, commentID), nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newStatusError("DeleteComment", resp.StatusCode, "")
+	}
+
+	return nil
+}
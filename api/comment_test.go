@@ -0,0 +1,145 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestListComments(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "username", username)
+		assert.Equal(t, "password", password)
+		assert.Equal(t, "/rest/api/latest/issue/1/comment", req.URL.Path)
+
+		if req.URL.Query().Get("startAt") == "0" {
+			writer.Write([]byte(`{"startAt":0,"maxResults":1,"total":2,"comments":[{"id":"10","body":"First"}]}`))
+			return
+		}
+
+		writer.Write([]byte(`{"startAt":1,"maxResults":1,"total":2,"comments":[{"id":"11","body":"Second"}]}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	comments, err := client.ListComments(jira.Issue{ID: "1", Key: "AB-124"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []jira.Comment{{ID: "10", Body: "First"}, {ID: "11", Body: "Second"}}, comments)
+}
+
+func TestGetComment(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/comment/10", req.URL.Path)
+		writer.Write([]byte(`{"id":"10","body":"Deployed","visibility":{"type":"role","value":"Developers"}}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	comment, err := client.GetComment(jira.Issue{ID: "1"}, "10")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Deployed", comment.Body)
+	assert.Equal(t, &jira.Visibility{Type: "role", Value: "Developers"}, comment.Visibility)
+}
+
+func TestUpdateComment(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/comment/10", req.URL.Path)
+		assert.JSONEq(t, `{"body":"Deployed to production","visibility":{"type":"group","value":"developers"}}`, string(body))
+		writer.Write([]byte(`{"id":"10","body":"Deployed to production"}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	comment := jira.Comment{
+		ID:         "10",
+		Body:       "Deployed to production",
+		Author:     &jira.User{Name: "username"},
+		Visibility: &jira.Visibility{Type: "group", Value: "developers"},
+	}
+	err := client.UpdateComment(jira.Issue{ID: "1"}, comment)
+
+	assert.Nil(t, err)
+}
+
+func TestUpdateCommentWithoutIDReturnsError(t *testing.T) {
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	err := client.UpdateComment(jira.Issue{ID: "1"}, jira.Comment{Body: "Deployed"})
+
+	assert.NotNil(t, err)
+}
+
+func TestDeleteComment(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "DELETE", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/comment/10", req.URL.Path)
+		writer.WriteHeader(http.StatusNoContent) // Set the status code to 204 - No content
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.DeleteComment(jira.Issue{ID: "1"}, "10")
+
+	assert.Nil(t, err)
+}
+
+func TestDeleteCommentReturnsError(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusForbidden) // Set the status code to 403 - Forbidden
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.DeleteComment(jira.Issue{ID: "1"}, "10")
+
+	assert.Equal(t, "DeleteComment response status is 403", err.Error())
+}
+
+func TestCreateComment(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/comment", req.URL.Path)
+		writer.WriteHeader(http.StatusCreated)
+		writer.Write([]byte(addCommentResponse))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	comment, err := client.CreateComment(jira.Issue{ID: "1"}, jira.Comment{Body: "A fine test message"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1", comment.ID)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIVersion is the version of the JIRA REST API used by the client
+type APIVersion string
+
+// Supported JIRA REST API versions. On JIRA Cloud latest resolves to version 2, which uses wiki markup for comment
+// bodies. Version 3 is only available on JIRA Cloud and uses the Atlassian Document Format
+const (
+	APIVersion2      APIVersion = "2"
+	APIVersion3      APIVersion = "3"
+	APIVersionLatest APIVersion = "latest"
+)
+
+// ParseAPIVersion returns the APIVersion for values like "2", "v3" or "latest", an empty value returns APIVersionLatest
+func ParseAPIVersion(value string) (APIVersion, error) {
+	switch strings.TrimPrefix(strings.ToLower(value), "v") {
+	case "2":
+		return APIVersion2, nil
+	case "3":
+		return APIVersion3, nil
+	case "latest", "":
+		return APIVersionLatest, nil
+	}
+
+	return "", fmt.Errorf("Unknown API version %v, expected one of %v, %v or %v", value, APIVersion2, APIVersion3, APIVersionLatest)
+}
+
+// SetAPIVersion sets the JIRA REST API version that is used for all requests
+func (c *Client) SetAPIVersion(version APIVersion) error {
+	if _, err := ParseAPIVersion(string(version)); err != nil {
+		return err
+	}
+
+	c.apiVersion = version
+	return nil
+}
+
+// APIVersion returns the JIRA REST API version that is used for all requests
+func (c *Client) APIVersion() APIVersion {
+	return c.apiVersion
+}
+
+// UserField returns the field that identifies users in requests of the selected REST API version
+func (c *Client) UserField() jira.UserField {
+	if c.apiVersion == APIVersion3 {
+		return jira.UserAccountID
+	}
+	return jira.UserName
+}
+
+// UserIdentifier returns the value that identifies the user in requests of the selected REST API version
+func (c *Client) UserIdentifier(user jira.User) string {
+	return user.Identifier(c.UserField())
+}
+
+// mentionFormat returns how mentions are written in wiki markup, with the same identifier as UserIdentifier
+func (c *Client) mentionFormat() adf.MentionFormat {
+	if c.UserField() == jira.UserAccountID {
+		return adf.MentionAccountID
+	}
+	return adf.MentionName
+}
+
+// endpoint returns the absolute url for the provided path in the selected REST API version
+func (c *Client) endpoint(path string, query url.Values) (*url.URL, error) {
+	endpointURL, err := url.Parse(fmt.Sprintf("rest/api/%s/%s", c.apiVersion, strings.TrimPrefix(path, "/")))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if query != nil {
+		endpointURL.RawQuery = query.Encode()
+	}
+
+	return c.baseURL.ResolveReference(endpointURL), nil
+}
+
+// newRequest returns an authenticated request for the endpoint at path, the payload is sent as JSON when it is not nil
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, payload interface{}) (*http.Request, error) {
+	endpointURL, err := c.endpoint(path, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		buffer := new(bytes.Buffer)
+		if err = json.NewEncoder(buffer).Encode(payload); err != nil {
+			return nil, err
+		}
+		body = buffer
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpointURL.String(), body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// do sends the request through the middleware chain in a span, and records mutations in the audit log
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.traceRequest(req, c.doer().Do)
+	c.audit(req, resp, err)
+	return resp, err
+}
+
+// decodeResponse decodes the JSON response body into v and closes the body
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// commentPayload converts the comment body to the representation expected by the selected REST API version:
+// an Atlassian Document Format document for version 3 and wiki markup for the other versions
+func (c *Client) commentPayload(comment jira.Comment) jira.Comment {
+	// The ID and author are read only and are not part of the payload
+	comment.ID = ""
+	comment.Author = nil
+
+	if c.apiVersion == APIVersion3 {
+		if comment.Document == nil && comment.Body != "" {
+			comment.Document = adf.FromText(comment.Body)
+			comment.Body = ""
+		}
+		return comment
+	}
+
+	if comment.Document != nil {
+		comment.Body = adf.ToWiki(comment.Document, c.mentionFormat())
+		comment.Document = nil
+	}
+
+	return comment
+}
@@ -0,0 +1,152 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	for value, expected := range map[string]api.APIVersion{
+		"2":      api.APIVersion2,
+		"v3":     api.APIVersion3,
+		"latest": api.APIVersionLatest,
+		"":       api.APIVersionLatest,
+	} {
+		version, err := api.ParseAPIVersion(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, version)
+	}
+
+	_, err := api.ParseAPIVersion("4")
+	assert.NotNil(t, err)
+}
+
+func TestSetAPIVersion(t *testing.T) {
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	assert.Equal(t, api.APIVersionLatest, client.APIVersion())
+
+	assert.Nil(t, client.SetAPIVersion(api.APIVersion3))
+	assert.Equal(t, api.APIVersion3, client.APIVersion())
+
+	assert.NotNil(t, client.SetAPIVersion("4"))
+	assert.Equal(t, api.APIVersion3, client.APIVersion())
+}
+
+func TestSearchUsesAPIVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/rest/api/2/search", req.URL.Path)
+		writer.Write([]byte(searchResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion2)
+
+	issues, err := client.Search("fixVersion IS EMPTY")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(issues))
+}
+
+func TestAddCommentToIssueSendsDocumentWithAPIVersion3(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "/rest/api/3/issue/1/comment", req.URL.Path)
+		assert.JSONEq(t, `{"body":{"version":1,"type":"doc","content":[{"type":"paragraph",`+
+			`"content":[{"type":"text","text":"A fine test message"}]}]}}`, string(body))
+		writer.WriteHeader(http.StatusCreated) // Set the status code to 201 - Created
+		writer.Write([]byte(addCommentResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion3)
+
+	err := client.AddCommentToIssue(jira.Issue{ID: "1"}, jira.Comment{Body: "A fine test message"})
+	assert.Nil(t, err)
+}
+
+func TestAddCommentToIssueSendsWikiMarkupWithAPIVersion2(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "/rest/api/2/issue/1/comment", req.URL.Path)
+		assert.JSONEq(t, `{"body":"A *fine* test message"}`, string(body))
+		writer.WriteHeader(http.StatusCreated) // Set the status code to 201 - Created
+		writer.Write([]byte(addCommentResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion2)
+
+	comment, _ := jira.NewDocumentComment(adf.FromMarkdown("A **fine** test message"))
+	err := client.AddCommentToIssue(jira.Issue{ID: "1"}, *comment)
+	assert.Nil(t, err)
+}
+
+func TestAddCommentToIssueMentionsUsernameWithAPIVersion2(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.JSONEq(t, `{"body":"Released by [~username]"}`, string(body))
+		writer.WriteHeader(http.StatusCreated) // Set the status code to 201 - Created
+		writer.Write([]byte(addCommentResponse))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion2)
+
+	user := jira.User{Name: "username", AccountID: "5b10ac8d82e05b22cc7d4ef5"}
+	document := adf.NewDocument(adf.Paragraph(adf.Text("Released by "), adf.Mention(client.UserIdentifier(user), "")))
+	comment, _ := jira.NewDocumentComment(document)
+	err := client.AddCommentToIssue(jira.Issue{ID: "1"}, *comment)
+	assert.Nil(t, err)
+	assert.Equal(t, jira.UserName, client.UserField())
+}
+
+func TestMyself(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/rest/api/3/myself", req.URL.Path)
+		writer.Write([]byte(`{"accountId":"5b10ac8d82e05b22cc7d4ef5","displayName":"Test user"}`))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion3)
+
+	user, err := client.Myself()
+	assert.Nil(t, err)
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", client.UserIdentifier(*user))
+}
+
+func TestUserIdentifierV2(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/rest/api/2/myself", req.URL.Path)
+		writer.Write([]byte(`{"name":"username","key":"JIRAUSER10100","displayName":"Test user"}`))
+	})
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetAPIVersion(api.APIVersion2)
+
+	user, err := client.Myself()
+	assert.Nil(t, err)
+	assert.Equal(t, "username", client.UserIdentifier(*user))
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", client.UserIdentifier(jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5"}))
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is returned when JIRA responds with an unexpected status code
+type StatusError struct {
+	// Operation is the client method that made the request
+	Operation  string
+	StatusCode int
+	// Message is the error message from the response body, when JIRA provided one
+	Message string
+}
+
+func newStatusError(operation string, statusCode int, message string) *StatusError {
+	return &StatusError{Operation: operation, StatusCode: statusCode, Message: message}
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%v response status is %v", e.Operation, e.StatusCode)
+}
+
+// IsAuthError reports if the error is caused by missing or invalid credentials, or by missing permissions
+func IsAuthError(err error) bool {
+	var statusError *StatusError
+	if !errors.As(err, &statusError) {
+		return false
+	}
+
+	return statusError.StatusCode == http.StatusUnauthorized || statusError.StatusCode == http.StatusForbidden
+}
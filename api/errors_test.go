@@ -0,0 +1,49 @@
+package api_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestSearchUnauthorizedReturnsAuthError(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusUnauthorized)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "wrong-password")
+	client.SetHTTPClient(httpClient)
+
+	_, err := client.Search("project = 1337")
+
+	var statusError *api.StatusError
+	assert.True(t, errors.As(err, &statusError))
+	assert.Equal(t, "Search", statusError.Operation)
+	assert.Equal(t, http.StatusUnauthorized, statusError.StatusCode)
+	assert.EqualError(t, err, "Search response status is 401")
+	assert.True(t, api.IsAuthError(err))
+}
+
+func TestStatusErrorUsesMessage(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+		writer.Write([]byte(`{"errorMessages":[],"errors":{"name":"Comment body can not be empty!"}}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	_, err := client.CreateComment(jira.Issue{ID: "1"}, jira.Comment{})
+
+	assert.EqualError(t, err, "Comment body can not be empty!")
+	assert.False(t, api.IsAuthError(err))
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"github.com/marcelblijleven/version-meister/jira"
+)
+
+// IssueSearcher finds JIRA issues
+type IssueSearcher interface {
+	Search(jql string) ([]jira.Issue, error)
+	GetIssue(key string) (*jira.Issue, error)
+}
+
+// VersionManager reads JIRA projects and creates, finds, releases and deletes their versions
+type VersionManager interface {
+	GetProject(projectID int) (*jira.Project, error)
+	ProjectVersions(projectID int) ([]jira.Version, error)
+	FindVersion(projectID int, name string) (*jira.Version, error)
+	CreateVersion(version jira.Version) error
+	ReleaseVersion(version jira.Version) error
+	DeleteVersion(version jira.Version) error
+}
+
+// IssueUpdater changes the fixVersions and the workflow status of JIRA issues
+type IssueUpdater interface {
+	AddVersionToIssue(issue jira.Issue, version jira.Version) error
+	RemoveVersionFromIssue(issue jira.Issue, version jira.Version) error
+	GetTransitions(issue jira.Issue) ([]jira.Transition, error)
+	TransitionIssue(issue jira.Issue, transitionID string) error
+}
+
+// Commenter reads and writes the comments on JIRA issues
+type Commenter interface {
+	ListComments(issue jira.Issue) ([]jira.Comment, error)
+	GetComment(issue jira.Issue, commentID string) (*jira.Comment, error)
+	AddCommentToIssue(issue jira.Issue, comment jira.Comment) error
+	CreateComment(issue jira.Issue, comment jira.Comment) (*jira.Comment, error)
+	UpdateComment(issue jira.Issue, comment jira.Comment) error
+	DeleteComment(issue jira.Issue, commentID string) error
+}
+
+// ChangelogReader reads the change history of JIRA issues
+type ChangelogReader interface {
+	GetIssueChangelog(issue jira.Issue) ([]jira.ChangelogEntry, error)
+}
+
+// JiraAPI is everything the release commands need from JIRA. Client implements it, fakes and decorators can be
+// used in its place
+type JiraAPI interface {
+	IssueSearcher
+	VersionManager
+	IssueUpdater
+	Commenter
+	ChangelogReader
+}
+
+var _ JiraAPI = (*Client)(nil)
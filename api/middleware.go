@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header that the RequestID middleware sets
+const RequestIDHeader = "X-Request-ID"
+
+// Doer sends a request and returns the response, *http.Client is a Doer
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc makes a function a Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer that sends requests, so it can change requests and responses or observe them
+type Middleware func(next Doer) Doer
+
+// Use adds middleware to the chain that every request of the client passes through. Middleware that is added first
+// sees the request first and the response last
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// doer returns the http client wrapped in the middleware chain
+func (c *Client) doer() Doer {
+	var doer Doer = c.httpClient
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+	return doer
+}
+
+// Logging returns middleware that writes the method, url, status and duration of every request to out
+func Logging(out io.Writer) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			duration := time.Since(start).Round(time.Millisecond)
+
+			prefix := ""
+			if id := req.Header.Get(RequestIDHeader); id != "" {
+				prefix = "[" + id + "] "
+			}
+
+			if err != nil {
+				fmt.Fprintf(out, "%v%v %v failed after %v: %v\n", prefix, req.Method, req.URL, duration, err)
+			} else {
+				fmt.Fprintf(out, "%v%v %v %v (%v)\n", prefix, req.Method, req.URL, resp.StatusCode, duration)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// RequestID returns middleware that sets a random ID in the RequestIDHeader of every request that has none, so
+// requests can be found in the logs of JIRA and of proxies
+func RequestID() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				id, err := newRequestID()
+				if err != nil {
+					return nil, err
+				}
+				req.Header.Set(RequestIDHeader, id)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// Header returns middleware that sets the header on every request
+func Header(name, value string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(name, value)
+			return next.Do(req)
+		})
+	}
+}
+
+func newRequestID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestUseAppliesMiddlewareInOrder(t *testing.T) {
+	var seen []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		seen = append(seen, "server "+req.Header.Get("X-Team"))
+		writer.Write([]byte(`{"name":"username"}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	trace := func(name string) api.Middleware {
+		return func(next api.Doer) api.Doer {
+			return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				seen = append(seen, "before "+name)
+				resp, err := next.Do(req)
+				seen = append(seen, "after "+name)
+				return resp, err
+			})
+		}
+	}
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.Use(trace("first"), api.Header("X-Team", "release"))
+	client.Use(trace("second"))
+
+	_, err := client.Myself()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"before first", "before second", "server release", "after second", "after first"}, seen)
+}
+
+func TestRequestIDAndLogging(t *testing.T) {
+	var ids []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		ids = append(ids, req.Header.Get(api.RequestIDHeader))
+		writer.WriteHeader(http.StatusNotFound)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	log := new(bytes.Buffer)
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.Use(api.RequestID(), api.Logging(log))
+
+	client.GetIssue("AB-1")
+	client.GetIssue("AB-2")
+
+	assert.Len(t, ids, 2)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{16}$`), ids[0])
+	assert.NotEqual(t, ids[0], ids[1])
+	assert.Regexp(t, regexp.MustCompile(`^\[`+ids[0]+`\] GET http://fake.com/rest/api/latest/issue/AB-1 404 \(\d+m?s\)\n`), log.String())
+}
+
+func TestRequestIDKeepsExistingID(t *testing.T) {
+	var id string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		id = req.Header.Get(api.RequestIDHeader)
+		writer.Write([]byte(`{"name":"username"}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.Use(api.Header(api.RequestIDHeader, "release-42"), api.RequestID())
+
+	_, err := client.Myself()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "release-42", id)
+}
+
+func TestLoggingLogsErrors(t *testing.T) {
+	log := new(bytes.Buffer)
+	client, _ := api.NewClient("http://127.0.0.1:1", "username", "password")
+	client.Use(api.Logging(log))
+
+	_, err := client.Myself()
+
+	assert.NotNil(t, err)
+	assert.Contains(t, log.String(), "GET http://127.0.0.1:1/rest/api/latest/myself failed after")
+}
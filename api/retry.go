@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryOptions configures the Retry middleware
+type RetryOptions struct {
+	// MaxRetries is the number of times a request is retried, 3 when it is 0
+	MaxRetries int
+	// MaxWait caps the time to wait before a retry, 30 seconds when it is 0
+	MaxWait time.Duration
+	// OnRetry is called before waiting for a retry with the status of the response that is retried
+	OnRetry func(req *http.Request, status int, wait time.Duration)
+}
+
+// Retry returns middleware that retries requests that JIRA rate limited (429) or that failed because JIRA was
+// unavailable (503). It waits as long as the Retry-After header asks, or backs off exponentially from one second
+func Retry(options RetryOptions) Middleware {
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+	if options.MaxWait == 0 {
+		options.MaxWait = 30 * time.Second
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			for attempt := 0; ; attempt++ {
+				resp, err := next.Do(req)
+				if err != nil || attempt == options.MaxRetries || !retryable(resp.StatusCode) {
+					return resp, err
+				}
+
+				if req.Body != nil && req.GetBody == nil {
+					// The body was consumed and cannot be sent again
+					return resp, err
+				}
+
+				wait := retryAfter(resp, attempt)
+				if wait > options.MaxWait {
+					wait = options.MaxWait
+				}
+				resp.Body.Close()
+
+				if options.OnRetry != nil {
+					options.OnRetry(req, resp.StatusCode, wait)
+				}
+
+				select {
+				case <-time.After(wait):
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+
+				if req.GetBody != nil {
+					if req.Body, err = req.GetBody(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		})
+	}
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// retryAfter returns the wait from the Retry-After header in seconds or as a date, or the exponential backoff
+func retryAfter(resp *http.Response, attempt int) time.Duration {
+	header := resp.Header.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+
+	return time.Second << uint(attempt)
+}
@@ -0,0 +1,84 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryRetriesRateLimitedRequests(t *testing.T) {
+	var bodies []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			writer.Header().Set("Retry-After", "0")
+			writer.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	var retries []int
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetOutput(ioutil.Discard)
+	client.Use(api.Retry(api.RetryOptions{OnRetry: func(req *http.Request, status int, wait time.Duration) {
+		retries = append(retries, status)
+		assert.Equal(t, time.Duration(0), wait)
+	}}))
+
+	err := client.AddVersionToIssue(jira.Issue{ID: "1", Key: "AB-1"}, jira.Version{Name: "1.0.0"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{429, 429}, retries)
+	assert.Len(t, bodies, 3)
+	assert.Equal(t, bodies[0], bodies[2])
+}
+
+func TestRetryStopsAfterMaxRetries(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.Use(api.Retry(api.RetryOptions{MaxRetries: 2, MaxWait: time.Millisecond}))
+
+	_, err := client.GetIssue("AB-1")
+
+	assert.Equal(t, "GetIssue response status is 503", err.Error())
+	assert.Equal(t, 3, requests)
+}
+
+func TestRetryDoesNotRetryOtherErrors(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusInternalServerError)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.Use(api.Retry(api.RetryOptions{}))
+
+	_, err := client.GetIssue("AB-1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the client
+const TracerName = "github.com/marcelblijleven/version-meister/api"
+
+// Span attributes that the client and the release runner set
+const (
+	AttributeIssueKey = attribute.Key("jira.issue.key")
+	AttributeVersion  = attribute.Key("jira.version")
+	AttributeJQL      = attribute.Key("jira.jql")
+	AttributeProject  = attribute.Key("jira.project.id")
+	AttributeComment  = attribute.Key("jira.comment.id")
+)
+
+// ContextBinder is implemented by JiraAPI implementations whose requests can be bound to a context
+type ContextBinder interface {
+	BindContext(ctx context.Context) JiraAPI
+}
+
+// WithContext returns the client with its requests bound to the context, so they are cancelled with it and their
+// spans are children of the span in it. Clients that do not implement ContextBinder are returned unchanged
+func WithContext(client JiraAPI, ctx context.Context) JiraAPI {
+	if binder, ok := client.(ContextBinder); ok {
+		return binder.BindContext(ctx)
+	}
+	return client
+}
+
+// WithContext returns a copy of the client that sends its requests with the context
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	client.ctx = ctx
+	return &client
+}
+
+// BindContext returns a copy of the client that sends its requests with the context
+func (c *Client) BindContext(ctx context.Context) JiraAPI {
+	return c.WithContext(ctx)
+}
+
+// SetTracerProvider sets the OpenTelemetry tracer provider, the global provider is used by default
+func (c *Client) SetTracerProvider(provider trace.TracerProvider) {
+	c.tracerProvider = provider
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *Client) tracer() trace.Tracer {
+	if c.tracerProvider == nil {
+		return otel.GetTracerProvider().Tracer(TracerName)
+	}
+	return c.tracerProvider.Tracer(TracerName)
+}
+
+// startSpan starts the span of a client operation as a child of the span in the context of the client
+func (c *Client) startSpan(name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer().Start(c.context(), "jira."+name, trace.WithAttributes(attributes...))
+}
+
+// endSpan ends the span and marks it as failed when there is an error
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceRequest sends the request in a client span with the method, path and status code, and propagates the trace
+// context to JIRA in the request headers
+func (c *Client) traceRequest(req *http.Request, send func(req *http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx, span := c.tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("server.address", req.URL.Hostname()),
+		))
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := send(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,164 @@
+package api_test
+
+import (
+	"context"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testTracerProvider returns a tracer provider that keeps the ended spans in the exporter
+func testTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestClientSpans(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	var traceparent string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("Traceparent")
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	provider, exporter := testTracerProvider()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "release")
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetOutput(ioutil.Discard)
+	client.SetTracerProvider(provider)
+
+	err := client.WithContext(ctx).AddVersionToIssue(jira.Issue{ID: "1", Key: "AB-1"}, jira.Version{Name: "1.2.0"})
+	parent.End()
+
+	assert.Nil(t, err)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+
+	request, operation := spans[0], spans[1]
+	assert.Equal(t, "HTTP PUT", request.Name)
+	assert.Equal(t, "jira.AddVersionToIssue", operation.Name)
+	assert.Equal(t, operation.SpanContext.SpanID(), request.Parent.SpanID())
+	assert.Equal(t, parent.SpanContext().SpanID(), operation.Parent.SpanID())
+
+	assert.Equal(t, "AB-1", attributes(operation)[api.AttributeIssueKey].AsString())
+	assert.Equal(t, "1.2.0", attributes(operation)[api.AttributeVersion].AsString())
+	assert.Equal(t, int64(204), attributes(request)["http.response.status_code"].AsInt64())
+	assert.Equal(t, "/rest/api/latest/issue/1", attributes(request)["url.path"].AsString())
+	assert.Contains(t, traceparent, request.SpanContext.SpanID().String())
+}
+
+func TestClientSpansRecordErrors(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	provider, exporter := testTracerProvider()
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetTracerProvider(provider)
+
+	_, err := client.Search("project = AB")
+
+	assert.NotNil(t, err)
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, int64(400), attributes(spans[0])["http.response.status_code"].AsInt64())
+	assert.Equal(t, "jira.Search", spans[1].Name)
+	assert.Equal(t, "project = AB", attributes(spans[1])[api.AttributeJQL].AsString())
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "Search response status is 400", spans[1].Status.Description)
+}
+
+func TestWithContextCancelsRequests(t *testing.T) {
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := api.WithContext(client, ctx).GetIssue("AB-1")
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientSpansForEveryOperation(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == "DELETE":
+			writer.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(req.URL.Path, "/versions"):
+			writer.Write([]byte(`[]`))
+		case strings.HasSuffix(req.URL.Path, "/comment"):
+			writer.Write([]byte(`{"comments":[],"total":0}`))
+		case strings.HasSuffix(req.URL.Path, "/transitions"):
+			writer.Write([]byte(`{"transitions":[]}`))
+		default:
+			writer.Write([]byte(`{}`))
+		}
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	provider, exporter := testTracerProvider()
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+	client.SetOutput(ioutil.Discard)
+	client.SetTracerProvider(provider)
+
+	issue := jira.Issue{ID: "1", Key: "AB-1"}
+	_, err := client.ListComments(issue)
+	assert.Nil(t, err)
+	_, err = client.GetComment(issue, "100")
+	assert.Nil(t, err)
+	assert.Nil(t, client.UpdateComment(issue, jira.Comment{ID: "100", Body: "Released"}))
+	assert.Nil(t, client.DeleteComment(issue, "100"))
+	_, err = client.ProjectVersions(1337)
+	assert.Nil(t, err)
+	_, err = client.Myself()
+	assert.Nil(t, err)
+	_, err = client.GetTransitions(issue)
+	assert.Nil(t, err)
+
+	operations := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		if strings.HasPrefix(span.Name, "jira.") {
+			operations[span.Name] = span
+		}
+	}
+
+	for _, name := range []string{"ListComments", "GetComment", "UpdateComment", "DeleteComment", "ProjectVersions",
+		"Myself", "GetTransitions"} {
+		assert.Contains(t, operations, "jira."+name)
+	}
+	assert.Equal(t, "AB-1", attributes(operations["jira.GetTransitions"])[api.AttributeIssueKey].AsString())
+	assert.Equal(t, "100", attributes(operations["jira.DeleteComment"])[api.AttributeComment].AsString())
+	assert.Equal(t, int64(1337), attributes(operations["jira.ProjectVersions"])[api.AttributeProject].AsInt64())
+}
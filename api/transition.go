@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/jira"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+)
+
+// transitionsResult represents the response from the transitions requests
+type transitionsResult struct {
+	Transitions []jira.Transition `json:"transitions"`
+}
+
+// transitionRequest allows for easy marshalling of transition data
+type transitionRequest struct {
+	Transition jira.Transition `json:"transition"`
+}
+
+// GetTransitions returns the workflow transitions that are currently available for the JIRA issue
+func (c *Client) GetTransitions(issue jira.Issue) (_ []jira.Transition, err error) {
+	ctx, span := c.startSpan("GetTransitions", AttributeIssueKey.String(issue.Key))
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("issue/%s/transitions", issue.ID), nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError("GetTransitions", resp.StatusCode, "")
+	}
+
+	var result transitionsResult
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Transitions, nil
+}
+
+// TransitionIssue performs the workflow transition with the provided ID on the JIRA issue
+func (c *Client) TransitionIssue(issue jira.Issue, transitionID string) (err error) {
+	ctx, span := c.startSpan("TransitionIssue", AttributeIssueKey.String(issue.Key), attribute.String("jira.transition.id", transitionID))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "TransitionIssue",
+		Issue:     issue.Key,
+		Before:    statusValues(issue),
+		After:     audit.Values{"transition": transitionID},
+	})
+	payload := transitionRequest{Transition: jira.Transition{ID: transitionID}}
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("issue/%s/transitions", issue.ID), nil, payload)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newStatusError("TransitionIssue", resp.StatusCode, "")
+	}
+
+	fmt.Fprintf(c.out, "Successfully transitioned issue %v\n", issue.Key)
+	return nil
+}
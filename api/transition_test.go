@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGetTransitions(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/transitions", req.URL.Path)
+		writer.Write([]byte(`{"transitions":[{"id":"31","name":"Release","to":{"id":"10001","name":"Done"}}]}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	transitions, err := client.GetTransitions(jira.Issue{ID: "1", Key: "AB-124"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []jira.Transition{{ID: "31", Name: "Release", To: &jira.Status{ID: "10001", Name: "Done"}}}, transitions)
+}
+
+func TestTransitionIssue(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/rest/api/latest/issue/1/transitions", req.URL.Path)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.JSONEq(t, `{"transition":{"id":"31"}}`, string(body))
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.TransitionIssue(jira.Issue{ID: "1", Key: "AB-124"}, "31")
+
+	assert.Nil(t, err)
+}
+
+func TestTransitionIssueReturnsError(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.TransitionIssue(jira.Issue{ID: "1", Key: "AB-124"}, "31")
+
+	assert.EqualError(t, err, "TransitionIssue response status is 400")
+}
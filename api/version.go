@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+)
+
+// GetProject returns the JIRA project with the provided ID
+func (c *Client) GetProject(projectID int) (_ *jira.Project, err error) {
+	ctx, span := c.startSpan("GetProject", AttributeProject.Int(projectID))
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("project/%d", projectID), nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError("GetProject", resp.StatusCode, "")
+	}
+
+	var project jira.Project
+	if err = decodeResponse(resp, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// ProjectVersions returns all versions of the JIRA project
+func (c *Client) ProjectVersions(projectID int) (_ []jira.Version, err error) {
+	ctx, span := c.startSpan("ProjectVersions", AttributeProject.Int(projectID))
+	defer func() { endSpan(span, err) }()
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("project/%d/versions", projectID), nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newStatusError("ProjectVersions", resp.StatusCode, "")
+	}
+
+	var versions []jira.Version
+	if err = decodeResponse(resp, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// FindVersion returns the version with the provided name in the JIRA project, or nil when it does not exist
+func (c *Client) FindVersion(projectID int, name string) (_ *jira.Version, err error) {
+	ctx, span := c.startSpan("FindVersion", AttributeVersion.String(name))
+	defer func() { endSpan(span, err) }()
+
+	versions, err := c.WithContext(ctx).ProjectVersions(projectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		if version.Name == name {
+			if version.ProjectID == 0 {
+				version.ProjectID = projectID
+			}
+			return &version, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ReleaseVersion marks the version as released on its release date, the version ID must be set
+func (c *Client) ReleaseVersion(version jira.Version) (err error) {
+	ctx, span := c.startSpan("ReleaseVersion", AttributeVersion.String(version.Name))
+	defer func() { endSpan(span, err) }()
+
+	if version.ID == "" {
+		return fmt.Errorf("Version ID cannot be empty")
+	}
+
+	update := map[string]interface{}{"released": true}
+	if version.ReleaseDate != "" {
+		update["releaseDate"] = version.ReleaseDate
+	}
+
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "ReleaseVersion",
+		Version:   version.Name,
+		Before:    audit.Values{"released": version.Released},
+		After:     audit.Values(update),
+	})
+	req, err := c.newRequest(ctx, "PUT", "version/"+version.ID, nil, update)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError("ReleaseVersion", resp.StatusCode, "")
+	}
+
+	fmt.Fprintln(c.out, "Successfully released version", version.Name)
+	return nil
+}
+
+// DeleteVersion deletes the version, the version ID must be set. Issues keep their other fixVersions
+func (c *Client) DeleteVersion(version jira.Version) (err error) {
+	ctx, span := c.startSpan("DeleteVersion", AttributeVersion.String(version.Name))
+	defer func() { endSpan(span, err) }()
+
+	if version.ID == "" {
+		return fmt.Errorf("Version ID cannot be empty")
+	}
+
+	ctx = withAudit(ctx, audit.Entry{
+		Operation: "DeleteVersion",
+		Version:   version.Name,
+		Before:    audit.Values{"name": version.Name, "released": version.Released},
+	})
+	req, err := c.newRequest(ctx, "DELETE", "version/"+version.ID, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newStatusError("DeleteVersion", resp.StatusCode, "")
+	}
+
+	fmt.Fprintln(c.out, "Successfully deleted version", version.Name)
+	return nil
+}
@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestGetProject(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/rest/api/latest/project/1337", req.URL.Path)
+		writer.Write([]byte(`{"id":"1337","key":"AB"}`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	project, err := client.GetProject(1337)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &jira.Project{ID: "1337", Key: "AB"}, project)
+}
+
+func TestFindVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/rest/api/latest/project/1337/versions", req.URL.Path)
+		writer.Write([]byte(`[{"id":"1","name":"1.0.0","released":true},{"id":"2","name":"1.1.0","released":false}]`))
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	version, err := client.FindVersion(1337, "1.1.0")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &jira.Version{ID: "2", Name: "1.1.0", ProjectID: 1337}, version)
+
+	version, err = client.FindVersion(1337, "2.0.0")
+
+	assert.Nil(t, err)
+	assert.Nil(t, version)
+}
+
+func TestReleaseVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "/rest/api/latest/version/2", req.URL.Path)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.JSONEq(t, `{"released":true,"releaseDate":"2020-06-01"}`, string(body))
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.ReleaseVersion(jira.Version{ID: "2", Name: "1.1.0", ReleaseDate: "2020-06-01"})
+
+	assert.Nil(t, err)
+}
+
+func TestReleaseVersionWithoutIDReturnsError(t *testing.T) {
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+
+	err := client.ReleaseVersion(jira.Version{Name: "1.1.0"})
+
+	assert.EqualError(t, err, "Version ID cannot be empty")
+}
+
+func TestDeleteVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "DELETE", req.Method)
+		assert.Equal(t, "/rest/api/latest/version/2", req.URL.Path)
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	httpClient, closeServer := testHTTPClient(handler)
+	defer closeServer()
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(httpClient)
+
+	err := client.DeleteVersion(jira.Version{ID: "2", Name: "1.1.0"})
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,274 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of a recorded request
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// maxLineSize is the longest entry that Read accepts, comments can make entries large
+const maxLineSize = 1024 * 1024
+
+// Values holds the fields of an issue, version or comment before or after a change, e.g. "fixVersions" or "status"
+type Values map[string]interface{}
+
+// Entry is a single mutating request that was sent to JIRA
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Actor string    `json:"actor,omitempty"`
+	RunID string    `json:"runId,omitempty"`
+	// Operation is the client method that sent the request, e.g. AddVersionToIssue
+	Operation string `json:"operation"`
+	Method    string `json:"method"`
+	// Endpoint is the path of the request relative to rest/api/<version>/, e.g. issue/10001
+	Endpoint  string `json:"endpoint"`
+	RequestID string `json:"requestId,omitempty"`
+	Issue     string `json:"issue,omitempty"`
+	Version   string `json:"version,omitempty"`
+	// Before and After are the changed values, when the client knows them
+	Before  Values `json:"before,omitempty"`
+	After   Values `json:"after,omitempty"`
+	Outcome string `json:"outcome"`
+	Status  int    `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Log appends entries to a JSON lines file. The file is only ever appended to, every entry is written with a single
+// write so runs that share the file do not interleave their entries
+type Log struct {
+	path string
+
+	mu    sync.Mutex
+	runID string
+	actor string
+}
+
+// DefaultPath returns the file the audit log is written to in the user state directory, $XDG_STATE_HOME or
+// ~/.local/state, so runs from every working directory share it and it is never committed to a repository
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "version-meister", "audit.jsonl"), nil
+}
+
+// NewRunID returns a run ID for commands that do not have one, it starts with the time so IDs sort by start time
+func NewRunID() (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("Could not create run ID: %v", err)
+	}
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix), nil
+}
+
+// New returns a log that appends to the file, the file is created with the first entry
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file the log appends to
+func (l *Log) Path() string {
+	return l.path
+}
+
+// SetRunID sets the run ID of the entries that are recorded after it
+func (l *Log) SetRunID(runID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.runID = runID
+}
+
+// SetActor sets the actor of the entries that are recorded after it. The api client sets the user it is
+// authenticated as when no actor is set
+func (l *Log) SetActor(actor string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actor = actor
+}
+
+// Actor returns the actor of the entries
+func (l *Log) Actor() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.actor
+}
+
+// Record appends the entry to the file. The time, run ID and actor of the log are used when the entry has none
+func (l *Log) Record(entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.RunID == "" {
+		entry.RunID = l.runID
+	}
+	if entry.Actor == "" {
+		entry.Actor = l.actor
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Filter selects entries, empty fields match every entry
+type Filter struct {
+	Issue     string
+	Version   string
+	Actor     string
+	RunID     string
+	Operation string
+	Since     time.Time
+	Until     time.Time
+	// Failed only matches entries with OutcomeFailure
+	Failed bool
+}
+
+// Match reports if the entry matches all fields of the filter. Issue keys, actors and operations are compared
+// case insensitively
+func (f Filter) Match(entry Entry) bool {
+	switch {
+	case f.Issue != "" && !strings.EqualFold(f.Issue, entry.Issue):
+		return false
+	case f.Version != "" && f.Version != entry.Version:
+		return false
+	case f.Actor != "" && !strings.EqualFold(f.Actor, entry.Actor):
+		return false
+	case f.RunID != "" && f.RunID != entry.RunID:
+		return false
+	case f.Operation != "" && !strings.EqualFold(f.Operation, entry.Operation):
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !entry.Time.Before(f.Until):
+		return false
+	case f.Failed && entry.Outcome != OutcomeFailure:
+		return false
+	}
+	return true
+}
+
+// Read returns the entries in the file that match the filter, in the order they were recorded
+func Read(path string, filter Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("Could not read audit log %v line %v: %v", path, number, err)
+		}
+
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Could not read audit log %v: %v", path, err)
+	}
+
+	return entries, nil
+}
+
+// Write writes the entries to the writer, one line per entry with the changed values on the lines below it
+func Write(writer io.Writer, entries []Entry) error {
+	for _, entry := range entries {
+		subject := entry.Issue
+		if entry.Version != "" {
+			subject = strings.TrimSpace(subject + " " + entry.Version)
+		}
+
+		outcome := entry.Outcome
+		if entry.Status != 0 {
+			outcome = fmt.Sprintf("%v (%v)", outcome, entry.Status)
+		}
+		if entry.Error != "" {
+			outcome += ": " + entry.Error
+		}
+
+		if _, err := fmt.Fprintf(writer, "%v %v %v %v %v %v %v %v\n", entry.Time.Format(time.RFC3339),
+			orDash(entry.RunID), orDash(entry.Actor), entry.Operation, entry.Method, entry.Endpoint,
+			orDash(subject), outcome); err != nil {
+			return err
+		}
+
+		for _, change := range []struct {
+			name   string
+			values Values
+		}{{"before", entry.Before}, {"after", entry.After}} {
+			if len(change.values) == 0 {
+				continue
+			}
+
+			values, err := json.Marshal(change.values)
+			if err != nil {
+				return err
+			}
+
+			if _, err = fmt.Fprintf(writer, "  %v: %s\n", change.name, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package audit_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func tempLog(t *testing.T) (*audit.Log, func()) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.Nil(t, err)
+	return audit.New(filepath.Join(dir, "logs", "audit.jsonl")), func() { os.RemoveAll(dir) }
+}
+
+func TestRecordAppends(t *testing.T) {
+	log, cleanup := tempLog(t)
+	defer cleanup()
+
+	log.SetRunID("1337-1.2.0")
+	log.SetActor("username")
+	assert.Nil(t, log.Record(audit.Entry{Operation: "CreateVersion", Method: "POST", Endpoint: "version", Version: "1.2.0", Outcome: audit.OutcomeSuccess}))
+
+	log.SetRunID("nightly")
+	assert.Nil(t, log.Record(audit.Entry{Operation: "AddVersionToIssue", Method: "PUT", Endpoint: "issue/10001", Issue: "AB-1", Actor: "other", Outcome: audit.OutcomeFailure, Status: 403}))
+
+	entries, err := audit.Read(log.Path(), audit.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "1337-1.2.0", entries[0].RunID)
+	assert.Equal(t, "username", entries[0].Actor)
+	assert.WithinDuration(t, time.Now(), entries[0].Time, time.Minute)
+	assert.Equal(t, "nightly", entries[1].RunID)
+	assert.Equal(t, "other", entries[1].Actor)
+
+	content, _ := ioutil.ReadFile(log.Path())
+	assert.Regexp(t, regexp.MustCompile(`^\{"time":"[^"]+","actor":"username","runId":"1337-1.2.0","operation":"CreateVersion",`), string(content))
+}
+
+func TestRecordConcurrently(t *testing.T) {
+	log, cleanup := tempLog(t)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, log.Record(audit.Entry{Operation: "AddVersionToIssue", Outcome: audit.OutcomeSuccess}))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := audit.Read(log.Path(), audit.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, entries, 20)
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	entry := audit.Entry{
+		Time:      now,
+		Actor:     "username",
+		RunID:     "1337-1.2.0",
+		Operation: "AddVersionToIssue",
+		Issue:     "AB-1",
+		Version:   "1.2.0",
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	assert.True(t, audit.Filter{}.Match(entry))
+	assert.True(t, audit.Filter{Issue: "ab-1", Version: "1.2.0", Actor: "USERNAME", RunID: "1337-1.2.0", Operation: "addversiontoissue"}.Match(entry))
+	assert.True(t, audit.Filter{Since: now, Until: now.Add(time.Second)}.Match(entry))
+	assert.False(t, audit.Filter{Issue: "AB-2"}.Match(entry))
+	assert.False(t, audit.Filter{Version: "1.3.0"}.Match(entry))
+	assert.False(t, audit.Filter{RunID: "nightly"}.Match(entry))
+	assert.False(t, audit.Filter{Since: now.Add(time.Second)}.Match(entry))
+	assert.False(t, audit.Filter{Until: now}.Match(entry))
+	assert.False(t, audit.Filter{Failed: true}.Match(entry))
+}
+
+func TestReadInvalidLineReturnsError(t *testing.T) {
+	log, cleanup := tempLog(t)
+	defer cleanup()
+
+	assert.Nil(t, log.Record(audit.Entry{Operation: "CreateVersion"}))
+	file, _ := os.OpenFile(log.Path(), os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString("not json\n")
+	file.Close()
+
+	_, err := audit.Read(log.Path(), audit.Filter{})
+
+	assert.Contains(t, err.Error(), "Could not read audit log "+log.Path()+" line 2:")
+}
+
+func TestWrite(t *testing.T) {
+	entries := []audit.Entry{
+		{
+			Time:      time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			Actor:     "username",
+			RunID:     "1337-1.2.0",
+			Operation: "AddVersionToIssue",
+			Method:    "PUT",
+			Endpoint:  "issue/10001",
+			Issue:     "AB-1",
+			Version:   "1.2.0",
+			Before:    audit.Values{"fixVersions": []string{}},
+			After:     audit.Values{"fixVersions": []string{"1.2.0"}},
+			Outcome:   audit.OutcomeSuccess,
+			Status:    204,
+		},
+		{
+			Time:      time.Date(2026, 10, 19, 12, 1, 0, 0, time.UTC),
+			Operation: "ReleaseVersion",
+			Method:    "PUT",
+			Endpoint:  "version/10000",
+			Outcome:   audit.OutcomeFailure,
+			Error:     "connection refused",
+		},
+	}
+
+	output := new(bytes.Buffer)
+	assert.Nil(t, audit.Write(output, entries))
+
+	assert.Equal(t, `2026-10-19T12:00:00Z 1337-1.2.0 username AddVersionToIssue PUT issue/10001 AB-1 1.2.0 success (204)
+  before: {"fixVersions":[]}
+  after: {"fixVersions":["1.2.0"]}
+2026-10-19T12:01:00Z - - ReleaseVersion PUT version/10000 - failure: connection refused
+`, output.String())
+}
+
+func TestNewRunID(t *testing.T) {
+	first, err := audit.NewRunID()
+	assert.Nil(t, err)
+	second, err := audit.NewRunID()
+	assert.Nil(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^\d{8}T\d{6}-[0-9a-f]{8}$`), first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestDefaultPath(t *testing.T) {
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+
+	os.Setenv("XDG_STATE_HOME", "/state")
+	path, err := audit.DefaultPath()
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("/state", "version-meister", "audit.jsonl"), path)
+
+	os.Setenv("XDG_STATE_HOME", "")
+	os.Setenv("HOME", "/home/jdoe")
+	path, err = audit.DefaultPath()
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("/home/jdoe", ".local", "state", "version-meister", "audit.jsonl"), path)
+}
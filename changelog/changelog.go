@@ -0,0 +1,251 @@
+package changelog
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Keep a Changelog section names, see https://keepachangelog.com
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+// sectionOrder is the order in which sections are written in a release
+var sectionOrder = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// DefaultMapping maps JIRA issue types to Keep a Changelog sections
+var DefaultMapping = map[string]string{
+	"Story":         Added,
+	"New Feature":   Added,
+	"Epic":          Added,
+	"Improvement":   Changed,
+	"Task":          Changed,
+	"Sub-task":      Changed,
+	"Bug":           Fixed,
+	"Vulnerability": Security,
+}
+
+// Release represents a single dated version section in a changelog
+type Release struct {
+	Version  string
+	Date     string
+	Sections map[string][]string
+}
+
+// NewRelease returns a Release in which every issue is put in the section its issue type maps to.
+// Issues with an unmapped issue type are added to the Changed section
+func NewRelease(version, date string, issues []jira.Issue, mapping map[string]string) (*Release, error) {
+	if version == "" {
+		return nil, fmt.Errorf("Version cannot be empty")
+	}
+
+	if mapping == nil {
+		mapping = DefaultMapping
+	}
+
+	sorted := make([]jira.Issue, len(issues))
+	copy(sorted, issues)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	release := Release{
+		Version:  version,
+		Date:     date,
+		Sections: make(map[string][]string),
+	}
+
+	for _, issue := range sorted {
+		issueType, summary := "", issue.Summary
+
+		if issue.Fields != nil {
+			if issue.Fields.IssueType != nil {
+				issueType = issue.Fields.IssueType.Name
+			}
+			if issue.Fields.Summary != "" {
+				summary = issue.Fields.Summary
+			}
+		}
+
+		section, ok := mapping[issueType]
+		if !ok {
+			section = Changed
+		}
+
+		release.Sections[section] = append(release.Sections[section], strings.TrimSpace(issue.Key+" "+summary))
+	}
+
+	return &release, nil
+}
+
+// ParseMapping parses a comma separated list of issueType=Section pairs, e.g. "Bug=Fixed,Story=Added".
+// The pairs are added on top of the DefaultMapping
+func ParseMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for issueType, section := range DefaultMapping {
+		mapping[issueType] = section
+	}
+
+	if value == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("Received incorrect section mapping %v, expected issueType=Section", pair)
+		}
+
+		section := strings.TrimSpace(parts[1])
+		if !isSection(section) {
+			return nil, fmt.Errorf("Unknown section %v, expected one of %v", section, strings.Join(sectionOrder, ", "))
+		}
+
+		mapping[strings.TrimSpace(parts[0])] = section
+	}
+
+	return mapping, nil
+}
+
+func isSection(name string) bool {
+	for _, section := range sectionOrder {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// heading returns the markdown heading of the release
+func (r *Release) heading() string {
+	if r.Date == "" {
+		return fmt.Sprintf("## [%s]", r.Version)
+	}
+	return fmt.Sprintf("## [%s] - %s", r.Version, r.Date)
+}
+
+// lines returns the release as markdown lines, ending with an empty line
+func (r *Release) lines() []string {
+	lines := []string{r.heading(), ""}
+
+	for _, section := range sectionOrder {
+		entries := r.Sections[section]
+		if len(entries) == 0 {
+			continue
+		}
+
+		lines = append(lines, "### "+section)
+		for _, entry := range entries {
+			lines = append(lines, "- "+entry)
+		}
+		lines = append(lines, "")
+	}
+
+	return lines
+}
+
+// String returns the release as a markdown section
+func (r *Release) String() string {
+	return strings.Join(r.lines(), "\n")
+}
+
+// Insert adds the release to the changelog content and returns the updated content.
+// The release is placed above the latest released version, below an [Unreleased] section.
+// If the changelog already contains the version, that section is replaced, which makes Insert idempotent
+func Insert(content string, release *Release) string {
+	lines := strings.Split(content, "\n")
+	versionHeading := fmt.Sprintf("## [%s]", release.Version)
+
+	// Replace the existing section of this version
+	for i, line := range lines {
+		if !strings.HasPrefix(line, versionHeading) {
+			continue
+		}
+
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if strings.HasPrefix(lines[j], "## ") || isLinkReference(lines[j]) {
+				end = j
+				break
+			}
+		}
+
+		replaced := append([]string{}, lines[:i]...)
+		replaced = append(replaced, release.lines()...)
+		replaced = append(replaced, lines[end:]...)
+		return strings.Join(replaced, "\n")
+	}
+
+	// Insert before the latest released version
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## [") || strings.HasPrefix(strings.ToLower(line), "## [unreleased]") {
+			continue
+		}
+
+		inserted := append([]string{}, lines[:i]...)
+		inserted = append(inserted, release.lines()...)
+		inserted = append(inserted, lines[i:]...)
+		return strings.Join(inserted, "\n")
+	}
+
+	// No released versions yet, append the release before any link references
+	end := len(lines)
+	for i, line := range lines {
+		if isLinkReference(line) {
+			end = i
+			break
+		}
+	}
+
+	head := lines[:end]
+	for len(head) > 0 && strings.TrimSpace(head[len(head)-1]) == "" {
+		head = head[:len(head)-1]
+	}
+
+	appended := append([]string{}, head...)
+	if len(appended) > 0 {
+		appended = append(appended, "")
+	}
+	appended = append(appended, release.lines()...)
+
+	if end < len(lines) {
+		appended = append(appended, lines[end:]...)
+	}
+
+	return strings.Join(appended, "\n")
+}
+
+// isLinkReference reports if the line is a markdown link reference definition, e.g. "[1.0.0]: https://..."
+func isLinkReference(line string) bool {
+	return strings.HasPrefix(line, "[") && strings.Contains(line, "]: ")
+}
+
+// header is used when a new changelog file is created
+const header = `# Changelog
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/).
+`
+
+// UpdateFile inserts the release into the changelog file at path, the file is created when it does not exist
+func UpdateFile(path string, release *Release) error {
+	content, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		content = []byte(header)
+	} else if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(Insert(string(content), release)), 0644)
+}
@@ -0,0 +1,129 @@
+package changelog_test
+
+import (
+	"github.com/marcelblijleven/version-meister/changelog"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const existingChangelog = `# Changelog
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.0.0] - 2020-01-01
+
+### Added
+- AB-1 First release
+
+[1.0.0]: https://example.com/compare/v0.1.0...v1.0.0
+`
+
+func testRelease(t *testing.T) *changelog.Release {
+	issues := []jira.Issue{
+		{Key: "AB-3", Fields: &jira.IssueFields{Summary: "Fix crash", IssueType: &jira.IssueType{Name: "Bug"}}},
+		{Key: "AB-2", Fields: &jira.IssueFields{Summary: "Add export", IssueType: &jira.IssueType{Name: "Story"}}},
+		{Key: "AB-4", Fields: &jira.IssueFields{Summary: "Update docs", IssueType: &jira.IssueType{Name: "Documentation"}}},
+	}
+	release, err := changelog.NewRelease("1.1.0", "2020-01-16", issues, nil)
+	assert.Nil(t, err)
+	return release
+}
+
+func TestNewReleaseMapsIssueTypesToSections(t *testing.T) {
+	release := testRelease(t)
+
+	assert.Equal(t, []string{"AB-2 Add export"}, release.Sections[changelog.Added])
+	assert.Equal(t, []string{"AB-3 Fix crash"}, release.Sections[changelog.Fixed])
+	assert.Equal(t, []string{"AB-4 Update docs"}, release.Sections[changelog.Changed])
+}
+
+func TestNewReleaseEmptyVersionReturnsError(t *testing.T) {
+	release, err := changelog.NewRelease("", "2020-01-16", nil, nil)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, release)
+}
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := changelog.ParseMapping("Bug=Security, Documentation=Changed")
+
+	assert.Nil(t, err)
+	assert.Equal(t, changelog.Security, mapping["Bug"])
+	assert.Equal(t, changelog.Changed, mapping["Documentation"])
+	assert.Equal(t, changelog.Added, mapping["Story"])
+}
+
+func TestParseMappingUnknownSectionReturnsError(t *testing.T) {
+	mapping, err := changelog.ParseMapping("Bug=Broken")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, mapping)
+}
+
+func TestInsertAddsReleaseBelowUnreleased(t *testing.T) {
+	expected := `# Changelog
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.1.0] - 2020-01-16
+
+### Added
+- AB-2 Add export
+
+### Changed
+- AB-4 Update docs
+
+### Fixed
+- AB-3 Fix crash
+
+## [1.0.0] - 2020-01-01
+
+### Added
+- AB-1 First release
+
+[1.0.0]: https://example.com/compare/v0.1.0...v1.0.0
+`
+
+	result := changelog.Insert(existingChangelog, testRelease(t))
+	assert.Equal(t, expected, result)
+}
+
+func TestInsertIsIdempotent(t *testing.T) {
+	once := changelog.Insert(existingChangelog, testRelease(t))
+	twice := changelog.Insert(once, testRelease(t))
+
+	assert.Equal(t, once, twice)
+}
+
+func TestInsertWithoutReleasedVersions(t *testing.T) {
+	content := "# Changelog\n\n[Unreleased]: https://example.com\n"
+	expected := "# Changelog\n\n## [1.1.0] - 2020-01-16\n\n### Added\n- AB-2 Add export\n\n" +
+		"### Changed\n- AB-4 Update docs\n\n### Fixed\n- AB-3 Fix crash\n\n[Unreleased]: https://example.com\n"
+
+	once := changelog.Insert(content, testRelease(t))
+	assert.Equal(t, expected, once)
+	assert.Equal(t, once, changelog.Insert(once, testRelease(t)))
+}
+
+func TestUpdateFileCreatesChangelog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changelog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "CHANGELOG.md")
+	err = changelog.UpdateFile(path, testRelease(t))
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "# Changelog")
+	assert.Contains(t, string(content), "## [1.1.0] - 2020-01-16")
+}
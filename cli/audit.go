@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// AuditOptions holds the flags of the audit command
+type AuditOptions struct {
+	// File is the audit log to query, empty selects the log that the commands write to
+	File      string
+	Issue     string
+	Version   string
+	Actor     string
+	RunID     string
+	Operation string
+	Since     time.Time
+	Until     time.Time
+	Failed    bool
+	JSON      bool
+}
+
+// ParseAuditCommand uses Args to determine which flags were called
+func ParseAuditCommand(args []string) AuditOptions {
+	command := flag.NewFlagSet("audit", flag.ExitOnError)
+	file := command.String("file", "", "Audit log to query, defaults to the audit log the commands write to")
+	issue := command.String("issue", "", "Only show changes to the issue with this key")
+	version := command.String("version", "", "Only show changes involving the version with this name")
+	actor := command.String("actor", "", "Only show changes made by this user")
+	runID := command.String("runId", "", "Only show changes made by the run with this ID")
+	operation := command.String("operation", "", "Only show changes made by this operation, e.g. AddVersionToIssue")
+	since := command.String("since", "", "Only show changes made at or after this time. Use format 2006-01-02 or RFC 3339")
+	until := command.String("until", "", "Only show changes made before this time. Use format 2006-01-02 or RFC 3339")
+	failed := command.Bool("failed", false, "Only show requests that failed")
+	json := command.Bool("json", false, "Print the matching entries as JSON lines")
+
+	command.Parse(args)
+
+	sinceTime, sinceErr := parseTime(*since)
+	untilTime, untilErr := parseTime(*until)
+	if sinceErr != nil || untilErr != nil || command.NArg() > 0 {
+		for _, err := range []error{sinceErr, untilErr} {
+			if err != nil {
+				fmt.Fprintln(command.Output(), err)
+			}
+		}
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return AuditOptions{
+		File:      *file,
+		Issue:     *issue,
+		Version:   *version,
+		Actor:     *actor,
+		RunID:     *runID,
+		Operation: *operation,
+		Since:     sinceTime,
+		Until:     untilTime,
+		Failed:    *failed,
+		JSON:      *json,
+	}
+}
+
+// parseTime parses a date in local time or an RFC 3339 time, an empty value returns the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid time %v, use format 2006-01-02 or RFC 3339", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,53 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestParseAuditCommand(t *testing.T) {
+	args := []string{"-file", "audit.jsonl", "-issue", "AB-1", "-version", "1.2.0", "-actor", "username",
+		"-runId", "nightly", "-operation", "AddVersionToIssue", "-since", "2026-10-01", "-until", "2026-10-19T12:00:00Z",
+		"-failed", "-json"}
+	options := cli.ParseAuditCommand(args)
+	assert.Equal(t, "audit.jsonl", options.File)
+	assert.Equal(t, "AB-1", options.Issue)
+	assert.Equal(t, "1.2.0", options.Version)
+	assert.Equal(t, "username", options.Actor)
+	assert.Equal(t, "nightly", options.RunID)
+	assert.Equal(t, "AddVersionToIssue", options.Operation)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), options.Since)
+	assert.Equal(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), options.Until.UTC())
+	assert.True(t, options.Failed)
+	assert.True(t, options.JSON)
+}
+
+func TestParseAuditCommandDefaults(t *testing.T) {
+	options := cli.ParseAuditCommand(nil)
+	assert.Equal(t, "", options.File)
+	assert.True(t, options.Since.IsZero())
+	assert.False(t, options.Failed)
+}
+
+func TestParseAuditCommandExitsOnInvalidTime(t *testing.T) {
+	args := []string{"-since", "yesterday"}
+
+	if os.Getenv("DETACHED_PARSE_AUDIT_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseAuditCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseAuditCommandExitsOnInvalidTime")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_AUDIT_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"flag"
+	"os"
+)
+
+// ChangelogOptions holds the flags of the changelog command
+type ChangelogOptions struct {
+	ReleaseName string
+	ProjectID   int
+	Date        string
+	Path        string
+	Sections    string
+}
+
+// ParseChangelogCommand uses Args to determine which flags were called
+func ParseChangelogCommand(args []string) ChangelogOptions {
+	command := flag.NewFlagSet("changelog", flag.ExitOnError)
+	releaseName := command.String("name", "", "Name of the version")
+	projectID := command.Int("project", 0, "ID for the JIRA project")
+	date := command.String("date", "", "Optional date string to include as release date. Use format 2006-01-02")
+	path := command.String("file", "CHANGELOG.md", "Path to the changelog file")
+	sections := command.String("sections", "", "Optional issue type to section mapping, e.g. Bug=Fixed,Story=Added")
+
+	command.Parse(args)
+
+	if *releaseName == "" || *projectID == 0 {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return ChangelogOptions{
+		ReleaseName: *releaseName,
+		ProjectID:   *projectID,
+		Date:        *date,
+		Path:        *path,
+		Sections:    *sections,
+	}
+}
@@ -0,0 +1,38 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseChangelogCommand(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-date", "2020-01-16", "-sections", "Bug=Fixed"}
+	options := cli.ParseChangelogCommand(args)
+	assert.Equal(t, "Test-Version", options.ReleaseName)
+	assert.Equal(t, 1337, options.ProjectID)
+	assert.Equal(t, "2020-01-16", options.Date)
+	assert.Equal(t, "CHANGELOG.md", options.Path)
+	assert.Equal(t, "Bug=Fixed", options.Sections)
+}
+
+func TestParseChangelogCommandExitsWithMissingName(t *testing.T) {
+	args := []string{"-project", "1337"}
+
+	if os.Getenv("DETACHED_PARSE_CHANGELOG_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseChangelogCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseChangelogCommandExitsWithMissingName")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_CHANGELOG_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
@@ -2,24 +2,103 @@ package cli
 
 import (
 	"flag"
+	"fmt"
 	"os"
 )
 
+// CreateOptions holds the flags of the create command
+type CreateOptions struct {
+	ReleaseName string
+	ProjectID   int
+	Component   string
+	Date        string
+	DryRun      bool
+	FailOnEmpty bool
+	Interactive bool
+	From        string
+	To          string
+	Repository  string
+	GitLog      string
+	KeyPattern  string
+	// Comment options
+	Comment         bool
+	CommentTemplate string
+	BuildURL        string
+	Environment     string
+	// CommentVisibility restricts the release comment, e.g. role:Developers or group:jira-developers
+	CommentVisibility string
+}
+
+// UseGit reports if the issues should be derived from git history instead of a status based JQL query
+func (o CreateOptions) UseGit() bool {
+	return o.GitLog != "" || o.From != "" || o.To != ""
+}
+
 // ParseCreateCommand uses Args to determine which flags were called
 func ParseCreateCommand(args []string) (string, int, string, string, bool) {
+	options := ParseCreateOptions(args)
+	return options.ReleaseName, options.ProjectID, options.Component, options.Date, options.DryRun
+}
+
+// ParseCreateOptions uses Args to determine which flags were called, including the git flags
+func ParseCreateOptions(args []string) CreateOptions {
 	command := flag.NewFlagSet("create", flag.ExitOnError)
 	releaseName := command.String("name", "", "Name of the version")
 	projectID := command.Int("project", 0, "ID for the JIRA project")
 	component := command.String("component", "", "Optional JIRA Component to include in the JQL query")
 	date := command.String("date", "", "Optional date string to include as release date. Use format 2006-01-02")
 	dryRun := command.Bool("dryRun", false, "Use dry run to preview which issues would be affected")
+	interactive := command.Bool("interactive", false, "Review the matched issues, the version name and date before making changes")
+	failOnEmpty := command.Bool("failOnEmpty", false, "Exit with code 3 when no issues match")
+	from := command.String("from", "", "Optional git ref to start from, issues are taken from the commits in from..to")
+	to := command.String("to", "", "Optional git ref to end at, issues are taken from the commits in from..to")
+	repository := command.String("repo", ".", "Path to the git repository used with -from and -to")
+	gitLog := command.String("gitLog", "", "Optional file with git log output to take issues from, use - for stdin")
+	keyPattern := command.String("keyPattern", "", "Optional regular expression used to find issue keys in git history")
+	comment := command.Bool("comment", false, "Post a release comment to every issue that the version is added to")
+	commentTemplate := command.String("commentTemplate", "", "Optional text/template file for the release comment, implies -comment")
+	buildURL := command.String("buildURL", "", "Optional build URL to include in the release comment")
+	environment := command.String("environment", "", "Optional deploy environment to include in the release comment")
+	commentVisibility := command.String("commentVisibility", "", "Optional role:name or group:name that the release comment is restricted to")
 
 	command.Parse(args)
 
 	if *releaseName == "" || *projectID == 0 {
 		command.PrintDefaults()
-		os.Exit(1)
+		os.Exit(ExitUsage)
 	}
 
-	return *releaseName, *projectID, *component, *date, *dryRun
+	if *gitLog == "" && (*from == "") != (*to == "") {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	// The review answers are read from stdin, which -gitLog - already consumes
+	if *interactive && *gitLog == "-" {
+		fmt.Fprintln(command.Output(), "-interactive reads the answers from stdin and cannot be combined with -gitLog -")
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return CreateOptions{
+		ReleaseName: *releaseName,
+		ProjectID:   *projectID,
+		Component:   *component,
+		Date:        *date,
+		DryRun:      *dryRun,
+		FailOnEmpty: *failOnEmpty,
+		Interactive: *interactive,
+		From:        *from,
+		To:          *to,
+		Repository:  *repository,
+		GitLog:      *gitLog,
+		KeyPattern:  *keyPattern,
+
+		Comment:         *comment || *commentTemplate != "",
+		CommentTemplate: *commentTemplate,
+		BuildURL:        *buildURL,
+		Environment:     *environment,
+
+		CommentVisibility: *commentVisibility,
+	}
 }
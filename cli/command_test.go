@@ -36,3 +36,83 @@ func TestParseCreateCommandExitsWithMissingName(t *testing.T) {
 
 	assert.True(t, ok && !e.Success())
 }
+
+func TestParseCreateOptionsWithGitRefs(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-from", "v1.0.0", "-to", "v1.1.0", "-keyPattern", "AB-[0-9]+"}
+	options := cli.ParseCreateOptions(args)
+	assert.Equal(t, "v1.0.0", options.From)
+	assert.Equal(t, "v1.1.0", options.To)
+	assert.Equal(t, ".", options.Repository)
+	assert.Equal(t, "AB-[0-9]+", options.KeyPattern)
+	assert.True(t, options.UseGit())
+}
+
+func TestParseCreateOptionsWithoutGit(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337"}
+	options := cli.ParseCreateOptions(args)
+	assert.False(t, options.UseGit())
+	assert.False(t, options.FailOnEmpty)
+}
+
+func TestParseCreateOptionsWithFailOnEmpty(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-failOnEmpty"}
+	options := cli.ParseCreateOptions(args)
+	assert.True(t, options.FailOnEmpty)
+}
+
+func TestParseCreateOptionsWithInteractive(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-interactive", "-gitLog", "git.log"}
+	options := cli.ParseCreateOptions(args)
+	assert.True(t, options.Interactive)
+	assert.Equal(t, "git.log", options.GitLog)
+}
+
+func TestParseCreateOptionsExitsWithInteractiveAndGitLogFromStdin(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-interactive", "-gitLog", "-"}
+
+	if os.Getenv("DETACHED_PARSE_CREATE_INTERACTIVE") == "1" {
+		// In subprocess
+		cli.ParseCreateOptions(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseCreateOptionsExitsWithInteractiveAndGitLogFromStdin")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_CREATE_INTERACTIVE=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok)
+	assert.Equal(t, cli.ExitUsage, e.ExitCode())
+}
+
+func TestParseCreateOptionsExitsWithMissingToRef(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-from", "v1.0.0"}
+
+	if os.Getenv("DETACHED_PARSE_CREATE_OPTIONS") == "1" {
+		// In subprocess
+		cli.ParseCreateOptions(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseCreateOptionsExitsWithMissingToRef")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_CREATE_OPTIONS=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
+
+func TestParseCreateOptionsWithCommentTemplate(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-commentTemplate", "comment.tmpl",
+		"-buildURL", "https://ci.example.com/42", "-environment", "production", "-commentVisibility", "role:Developers"}
+	options := cli.ParseCreateOptions(args)
+	assert.True(t, options.Comment)
+	assert.Equal(t, "comment.tmpl", options.CommentTemplate)
+	assert.Equal(t, "https://ci.example.com/42", options.BuildURL)
+	assert.Equal(t, "production", options.Environment)
+	assert.Equal(t, "role:Developers", options.CommentVisibility)
+}
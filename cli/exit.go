@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/credentials"
+)
+
+// Exit codes of the command line, see ExitCode
+const (
+	ExitSuccess = 0
+	// ExitFailure is used for errors that have no more specific exit code
+	ExitFailure = 1
+	// ExitUsage is used for invalid flags and arguments
+	ExitUsage = 2
+	// ExitNoIssues is used when the JQL matched no issues and -failOnEmpty is set
+	ExitNoIssues = 3
+	// ExitPartialFailure is used when some changes were made before an error stopped the command
+	ExitPartialFailure = 4
+	// ExitAuthFailure is used when JIRA rejects the credentials, or no credentials were found
+	ExitAuthFailure = 5
+)
+
+// ErrNoIssues is returned when the JQL matched no issues and -failOnEmpty is set
+var ErrNoIssues = errors.New("No issues matched")
+
+// PartialError is returned when an error stopped a command after it already made some of its changes
+type PartialError struct {
+	Err       error
+	Completed int
+	Total     int
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%v (%v of %v changes were made)", e.Err, e.Completed, e.Total)
+}
+
+// Unwrap returns the error that stopped the command
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// NewPartialError returns err as a PartialError when some changes were completed, otherwise err is returned
+func NewPartialError(err error, completed, total int) error {
+	if err == nil || completed == 0 {
+		return err
+	}
+	return &PartialError{Err: err, Completed: completed, Total: total}
+}
+
+// ExitCode returns the exit code for the error. Authentication errors take precedence over partial failures
+func ExitCode(err error) int {
+	var partial *PartialError
+
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case api.IsAuthError(err) || errors.Is(err, credentials.ErrNotFound):
+		return ExitAuthFailure
+	case errors.Is(err, ErrNoIssues):
+		return ExitNoIssues
+	case errors.As(err, &partial):
+		return ExitPartialFailure
+	}
+
+	return ExitFailure
+}
@@ -0,0 +1,48 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	partial := cli.NewPartialError(errors.New("AddVersion response status is 500"), 3, 10)
+
+	assert.Equal(t, cli.ExitSuccess, cli.ExitCode(nil))
+	assert.Equal(t, cli.ExitFailure, cli.ExitCode(errors.New("Something broke")))
+	assert.Equal(t, cli.ExitNoIssues, cli.ExitCode(cli.ErrNoIssues))
+	assert.Equal(t, cli.ExitPartialFailure, cli.ExitCode(partial))
+	assert.Equal(t, cli.ExitAuthFailure, cli.ExitCode(fmt.Errorf("Could not get credentials: %w", credentials.ErrNotFound)))
+	assert.EqualError(t, partial, "AddVersion response status is 500 (3 of 10 changes were made)")
+}
+
+func TestNewPartialErrorWithoutCompletedChanges(t *testing.T) {
+	err := errors.New("Search response status is 500")
+
+	assert.Equal(t, err, cli.NewPartialError(err, 0, 10))
+	assert.Nil(t, cli.NewPartialError(nil, 3, 10))
+}
+
+func TestParseCommandExitsWithUsageCode(t *testing.T) {
+	if os.Getenv("DETACHED_PARSE_USAGE") == "1" {
+		// In subprocess
+		cli.ParseNotesCommand([]string{})
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseCommandExitsWithUsageCode")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_USAGE=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok)
+	assert.Equal(t, cli.ExitUsage, e.ExitCode())
+}
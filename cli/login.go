@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"flag"
+	"os"
+)
+
+// LoginOptions holds the flags of the login command
+type LoginOptions struct {
+	Username string
+	Store    string
+	File     string
+	Command  string
+}
+
+// ParseLoginCommand uses Args to determine which flags were called
+func ParseLoginCommand(args []string) LoginOptions {
+	command := flag.NewFlagSet("login", flag.ExitOnError)
+	username := command.String("username", "", "Username or email address, leave empty to store a personal access token")
+	store := command.String("store", "file", "Where to store the secret: file or command")
+	file := command.String("file", "", "Optional encrypted credentials file, defaults to the credentials_file of the profile")
+	storeCommand := command.String("command", "", "Optional command that stores the secret from stdin, e.g. \"pass insert -m jira/{host}\"")
+
+	command.Parse(args)
+
+	if *store != "file" && *store != "command" {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return LoginOptions{
+		Username: *username,
+		Store:    *store,
+		File:     *file,
+		Command:  *storeCommand,
+	}
+}
@@ -0,0 +1,43 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseLoginCommand(t *testing.T) {
+	args := []string{"-username", "me@example.com", "-store", "command", "-command", "pass insert -m jira/{host}"}
+	options := cli.ParseLoginCommand(args)
+	assert.Equal(t, "me@example.com", options.Username)
+	assert.Equal(t, "command", options.Store)
+	assert.Equal(t, "pass insert -m jira/{host}", options.Command)
+}
+
+func TestParseLoginCommandDefaults(t *testing.T) {
+	options := cli.ParseLoginCommand([]string{})
+	assert.Equal(t, "", options.Username)
+	assert.Equal(t, "file", options.Store)
+	assert.Equal(t, "", options.File)
+}
+
+func TestParseLoginCommandExitsWithUnknownStore(t *testing.T) {
+	args := []string{"-store", "keychain"}
+
+	if os.Getenv("DETACHED_PARSE_LOGIN_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseLoginCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseLoginCommandExitsWithUnknownStore")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_LOGIN_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
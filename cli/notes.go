@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"flag"
+	"os"
+)
+
+// NotesOptions holds the flags of the notes command
+type NotesOptions struct {
+	ReleaseName  string
+	ProjectID    int
+	GroupBy      string
+	Format       string
+	TemplatePath string
+	OutputPath   string
+}
+
+// ParseNotesCommand uses Args to determine which flags were called
+func ParseNotesCommand(args []string) NotesOptions {
+	command := flag.NewFlagSet("notes", flag.ExitOnError)
+	releaseName := command.String("name", "", "Name of the version")
+	projectID := command.Int("project", 0, "ID for the JIRA project")
+	groupBy := command.String("groupBy", "type", "Group issues by type, component or label")
+	format := command.String("format", "markdown", "Output format: markdown, html, text or confluence")
+	templatePath := command.String("template", "", "Optional text/template file that overrides the default template")
+	outputPath := command.String("out", "", "Optional file to write the release notes to, defaults to stdout")
+
+	command.Parse(args)
+
+	if *releaseName == "" || *projectID == 0 {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return NotesOptions{
+		ReleaseName:  *releaseName,
+		ProjectID:    *projectID,
+		GroupBy:      *groupBy,
+		Format:       *format,
+		TemplatePath: *templatePath,
+		OutputPath:   *outputPath,
+	}
+}
@@ -0,0 +1,46 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseNotesCommand(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-groupBy", "label", "-format", "html"}
+	options := cli.ParseNotesCommand(args)
+	assert.Equal(t, "Test-Version", options.ReleaseName)
+	assert.Equal(t, 1337, options.ProjectID)
+	assert.Equal(t, "label", options.GroupBy)
+	assert.Equal(t, "html", options.Format)
+	assert.Equal(t, "", options.TemplatePath)
+	assert.Equal(t, "", options.OutputPath)
+}
+
+func TestParseNotesCommandDefaults(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337"}
+	options := cli.ParseNotesCommand(args)
+	assert.Equal(t, "type", options.GroupBy)
+	assert.Equal(t, "markdown", options.Format)
+}
+
+func TestParseNotesCommandExitsWithMissingProject(t *testing.T) {
+	args := []string{"-name", "Test-Version"}
+
+	if os.Getenv("DETACHED_PARSE_NOTES_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseNotesCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseNotesCommandExitsWithMissingProject")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_NOTES_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
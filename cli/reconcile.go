@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"flag"
+	"os"
+)
+
+// ReconcileOptions holds the flags of the reconcile command
+type ReconcileOptions struct {
+	ReleaseName string
+	ProjectID   int
+	From        string
+	To          string
+	Repository  string
+	GitLog      string
+	KeyPattern  string
+	Fix         bool
+}
+
+// ParseReconcileCommand uses Args to determine which flags were called
+func ParseReconcileCommand(args []string) ReconcileOptions {
+	command := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	releaseName := command.String("name", "", "Name of the version")
+	projectID := command.Int("project", 0, "ID for the JIRA project")
+	from := command.String("from", "", "Git ref to start from, e.g. the previous release tag")
+	to := command.String("to", "", "Git ref to end at, e.g. the release tag")
+	repository := command.String("repo", ".", "Path to the git repository used with -from and -to")
+	gitLog := command.String("gitLog", "", "Optional file with git log output to use instead of -from and -to, use - for stdin")
+	keyPattern := command.String("keyPattern", "", "Optional regular expression used to find issue keys in git history")
+	fix := command.Bool("fix", false, "Add the version to issues that are referenced in git but are missing it in JIRA")
+
+	command.Parse(args)
+
+	if *releaseName == "" || *projectID == 0 || (*gitLog == "" && (*from == "" || *to == "")) {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return ReconcileOptions{
+		ReleaseName: *releaseName,
+		ProjectID:   *projectID,
+		From:        *from,
+		To:          *to,
+		Repository:  *repository,
+		GitLog:      *gitLog,
+		KeyPattern:  *keyPattern,
+		Fix:         *fix,
+	}
+}
@@ -0,0 +1,39 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseReconcileCommand(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337", "-from", "v1.0.0", "-to", "v1.1.0", "-fix"}
+	options := cli.ParseReconcileCommand(args)
+	assert.Equal(t, "Test-Version", options.ReleaseName)
+	assert.Equal(t, 1337, options.ProjectID)
+	assert.Equal(t, "v1.0.0", options.From)
+	assert.Equal(t, "v1.1.0", options.To)
+	assert.Equal(t, ".", options.Repository)
+	assert.True(t, options.Fix)
+}
+
+func TestParseReconcileCommandExitsWithoutGitHistory(t *testing.T) {
+	args := []string{"-name", "Test-Version", "-project", "1337"}
+
+	if os.Getenv("DETACHED_PARSE_RECONCILE_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseReconcileCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseReconcileCommandExitsWithoutGitHistory")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_RECONCILE_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
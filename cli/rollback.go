@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"flag"
+	"github.com/marcelblijleven/version-meister/journal"
+	"os"
+)
+
+// RollbackOptions holds the flags of the rollback command
+type RollbackOptions struct {
+	RunID       string
+	ReleaseName string
+	ProjectID   int
+	Journal     string
+	DryRun      bool
+}
+
+// ParseRollbackCommand uses Args to determine which flags were called
+func ParseRollbackCommand(args []string) RollbackOptions {
+	command := flag.NewFlagSet("rollback", flag.ExitOnError)
+	runID := command.String("runId", "", "ID of the run to roll back, defaults to the project and version")
+	releaseName := command.String("name", "", "Name of the version, used for the default run ID")
+	projectID := command.Int("project", 0, "ID for the JIRA project, used for the default run ID")
+	journalDir := command.String("journal", journal.DefaultDir(), "Directory the run journals are stored in")
+	dryRun := command.Bool("dryRun", false, "Print the changes that would be undone without making them")
+
+	command.Parse(args)
+
+	if *runID == "" && (*releaseName == "" || *projectID == 0) {
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return RollbackOptions{
+		RunID:       *runID,
+		ReleaseName: *releaseName,
+		ProjectID:   *projectID,
+		Journal:     *journalDir,
+		DryRun:      *dryRun,
+	}
+}
@@ -0,0 +1,44 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseRollbackCommand(t *testing.T) {
+	args := []string{"-name", "1.2.0", "-project", "1337", "-dryRun"}
+	options := cli.ParseRollbackCommand(args)
+	assert.Equal(t, "", options.RunID)
+	assert.Equal(t, "1.2.0", options.ReleaseName)
+	assert.Equal(t, 1337, options.ProjectID)
+	assert.Equal(t, ".version-meister/runs", options.Journal)
+	assert.True(t, options.DryRun)
+}
+
+func TestParseRollbackCommandWithRunID(t *testing.T) {
+	options := cli.ParseRollbackCommand([]string{"-runId", "nightly"})
+	assert.Equal(t, "nightly", options.RunID)
+	assert.False(t, options.DryRun)
+}
+
+func TestParseRollbackCommandExitsWithoutRun(t *testing.T) {
+	args := []string{"-name", "1.2.0"}
+
+	if os.Getenv("DETACHED_PARSE_ROLLBACK_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseRollbackCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseRollbackCommandExitsWithoutRun")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_ROLLBACK_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/journal"
+	"os"
+	"strings"
+)
+
+// Modes of the run command
+const (
+	RunPlan  = "plan"
+	RunApply = "apply"
+)
+
+// RunOptions holds the flags of the run command
+type RunOptions struct {
+	Mode        string
+	File        string
+	ProjectID   int
+	Vars        map[string]string
+	RunID       string
+	Journal     string
+	FailOnEmpty bool
+	Concurrency int
+}
+
+// variables collects repeated -var NAME=value flags
+type variables map[string]string
+
+func (v variables) String() string {
+	var pairs []string
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variables) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("Expected NAME=value, got %v", value)
+	}
+
+	v[parts[0]] = parts[1]
+	return nil
+}
+
+// ParseRunCommand uses Args to determine which flags were called, the first arg is the mode: plan or apply
+func ParseRunCommand(args []string) RunOptions {
+	command := flag.NewFlagSet("run", flag.ExitOnError)
+	file := command.String("file", "release.yaml", "Release file that describes the release steps")
+	projectID := command.Int("project", 0, "ID for the JIRA project, used when the release file has no project")
+	runID := command.String("runId", "", "Optional ID of the run, defaults to the project and version, used to resume a run")
+	journalDir := command.String("journal", journal.DefaultDir(), "Directory the run journals are stored in")
+	failOnEmpty := command.Bool("failOnEmpty", false, "Exit with code 3 when the queries match no issues")
+	concurrency := command.Int("concurrency", 1, "Number of issues that are updated at the same time")
+	vars := variables{}
+	command.Var(vars, "var", "Variable used in the release file as ${NAME}, e.g. -var VERSION=1.2.0, can be repeated")
+
+	// Flags may be given before or after the mode
+	command.Parse(args)
+	rest := command.Args()
+
+	if len(rest) < 1 || (rest[0] != RunPlan && rest[0] != RunApply) {
+		fmt.Fprintln(command.Output(), "Usage: run plan|apply [flags]")
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	command.Parse(rest[1:])
+
+	return RunOptions{
+		Mode:      rest[0],
+		File:      *file,
+		ProjectID: *projectID,
+		Vars:      vars,
+		RunID:     *runID,
+		Journal:   *journalDir,
+
+		FailOnEmpty: *failOnEmpty,
+		Concurrency: *concurrency,
+	}
+}
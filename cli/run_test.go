@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseRunCommand(t *testing.T) {
+	args := []string{"-project", "1337", "apply", "-file", "deploy/release.yaml", "-var", "VERSION=1.2.0", "-var", "ENV=prod", "-runId", "nightly", "-concurrency", "4"}
+	options := cli.ParseRunCommand(args)
+	assert.Equal(t, cli.RunApply, options.Mode)
+	assert.Equal(t, "deploy/release.yaml", options.File)
+	assert.Equal(t, 1337, options.ProjectID)
+	assert.Equal(t, map[string]string{"VERSION": "1.2.0", "ENV": "prod"}, options.Vars)
+	assert.Equal(t, "nightly", options.RunID)
+	assert.Equal(t, 4, options.Concurrency)
+}
+
+func TestParseRunCommandDefaults(t *testing.T) {
+	options := cli.ParseRunCommand([]string{"plan"})
+	assert.Equal(t, cli.RunPlan, options.Mode)
+	assert.Equal(t, "release.yaml", options.File)
+	assert.Empty(t, options.Vars)
+	assert.Equal(t, "", options.RunID)
+	assert.Equal(t, ".version-meister/runs", options.Journal)
+	assert.Equal(t, 1, options.Concurrency)
+}
+
+func TestParseRunCommandExitsWithUnknownMode(t *testing.T) {
+	args := []string{"destroy"}
+
+	if os.Getenv("DETACHED_PARSE_RUN_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseRunCommand(args)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseRunCommandExitsWithUnknownMode")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_RUN_COMMAND=1")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
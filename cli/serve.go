@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretEnv is the env variable that holds the webhook secret when -secret is not set
+const SecretEnv = "VERSION_MEISTER_WEBHOOK_SECRET"
+
+// ServeOptions holds the flags of the serve command
+type ServeOptions struct {
+	Address string
+	Path    string
+	Rules   string
+	Secret  string
+}
+
+// ParseServeCommand uses Args to determine which flags were called
+func ParseServeCommand(args []string) ServeOptions {
+	command := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := command.String("addr", ":8080", "Address the webhook server listens on")
+	path := command.String("path", "/webhook", "Path that JIRA sends the webhooks to")
+	rules := command.String("rules", "webhook.yaml", "Rules file that describes which versions are assigned on which events")
+	// The env variable is read after parsing, a flag default would be printed in the usage
+	secret := command.String("secret", "", "Shared secret of the webhook, defaults to the "+SecretEnv+" env variable")
+
+	command.Parse(args)
+
+	if *secret == "" {
+		*secret = os.Getenv(SecretEnv)
+	}
+
+	if *secret == "" || *rules == "" || !strings.HasPrefix(*path, "/") {
+		fmt.Fprintln(command.Output(), "A secret and a rules file are required, the path must start with /")
+		command.PrintDefaults()
+		os.Exit(ExitUsage)
+	}
+
+	return ServeOptions{
+		Address: *address,
+		Path:    *path,
+		Rules:   *rules,
+		Secret:  *secret,
+	}
+}
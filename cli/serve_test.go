@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseServeCommand(t *testing.T) {
+	args := []string{"-addr", "127.0.0.1:9000", "-path", "/jira", "-rules", "rules.yaml", "-secret", "s3cret"}
+	options := cli.ParseServeCommand(args)
+	assert.Equal(t, "127.0.0.1:9000", options.Address)
+	assert.Equal(t, "/jira", options.Path)
+	assert.Equal(t, "rules.yaml", options.Rules)
+	assert.Equal(t, "s3cret", options.Secret)
+}
+
+func TestParseServeCommandSecretFromEnv(t *testing.T) {
+	os.Setenv(cli.SecretEnv, "from-env")
+	defer os.Unsetenv(cli.SecretEnv)
+
+	options := cli.ParseServeCommand(nil)
+	assert.Equal(t, ":8080", options.Address)
+	assert.Equal(t, "/webhook", options.Path)
+	assert.Equal(t, "webhook.yaml", options.Rules)
+	assert.Equal(t, "from-env", options.Secret)
+}
+
+func TestParseServeCommandExitsWithoutSecret(t *testing.T) {
+	if os.Getenv("DETACHED_PARSE_SERVE_COMMAND") == "1" {
+		// In subprocess
+		cli.ParseServeCommand(nil)
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseServeCommandExitsWithoutSecret")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_SERVE_COMMAND=1", cli.SecretEnv+"=")
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok && !e.Success())
+}
+
+func TestParseServeCommandUsageDoesNotPrintSecret(t *testing.T) {
+	if os.Getenv("DETACHED_PARSE_SERVE_USAGE") == "1" {
+		// In subprocess
+		cli.ParseServeCommand([]string{"-path", "nope"})
+		return
+	}
+
+	// Create a command to run as subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestParseServeCommandUsageDoesNotPrintSecret")
+	cmd.Env = append(os.Environ(), "DETACHED_PARSE_SERVE_USAGE=1", cli.SecretEnv+"=topsecret123")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	// Cast err as ExitError
+	e, ok := err.(*exec.ExitError)
+
+	assert.True(t, ok)
+	assert.Equal(t, cli.ExitUsage, e.ExitCode())
+	assert.Contains(t, stderr.String(), "-secret")
+	assert.NotContains(t, stderr.String(), "topsecret123")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/output"
+	"os"
+)
+
+// runAudit prints the entries of the audit log that match the flags. The entries are written to stdout directly,
+// as text or with -json as JSON lines, so the output format flag does not apply
+func (a *app) runAudit(args []string) (*output.Result, error) {
+	options := cli.ParseAuditCommand(args)
+
+	file := options.File
+	if file == "" {
+		file = a.auditPath
+	}
+	if file == "" {
+		return nil, fmt.Errorf("No audit log to query, the audit log is disabled")
+	}
+
+	entries, err := audit.Read(file, audit.Filter{
+		Issue:     options.Issue,
+		Version:   options.Version,
+		Actor:     options.Actor,
+		RunID:     options.RunID,
+		Operation: options.Operation,
+		Since:     options.Since,
+		Until:     options.Until,
+		Failed:    options.Failed,
+	})
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No audit log found at %v", file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if options.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, entry := range entries {
+			if err = encoder.Encode(entry); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stdout, "No audit log entries match")
+		return nil, nil
+	}
+
+	return nil, audit.Write(os.Stdout, entries)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/changelog"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+)
+
+func (a *app) runChangelog(args []string) (*output.Result, error) {
+	options := cli.ParseChangelogCommand(a.withDefaults(args))
+
+	mapping, err := changelog.ParseMapping(options.Sections)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	date := options.Date
+	if date == "" {
+		date = a.today()
+	}
+
+	name, err := a.versionName(options.ReleaseName, date, options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The release date of the version keeps the section header the same when the command runs again on another day
+	if options.Date == "" {
+		version, err := client.FindVersion(options.ProjectID, name)
+		if err != nil {
+			return nil, err
+		}
+		if version != nil && version.ReleaseDate != "" {
+			date = version.ReleaseDate
+		}
+	}
+
+	jql, err := a.versionJQL(options.ProjectID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := client.Search(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := changelog.NewRelease(name, date, issues, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = changelog.UpdateFile(options.Path, release); err != nil {
+		return nil, err
+	}
+
+	result := &output.Result{
+		Command: "changelog",
+		Message: fmt.Sprintf("Successfully added version %v to %v", name, options.Path),
+		Version: output.NewVersionResult(jira.Version{Name: name, ReleaseDate: date, ProjectID: options.ProjectID}, false),
+	}
+	result.AddIssues(issues, "added")
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChangelogIsTheSameOnAnotherDay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changelog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	version := server.AddVersion(jira.Version{Name: "1.2.0", ReleaseDate: "2020-06-01", ProjectID: 1337})
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project:     jira.Project{ID: "1337"},
+		Summary:     "Fix crash on startup",
+		IssueType:   &jira.IssueType{Name: "Bug"},
+		FixVersions: []jira.Version{version},
+	}})
+
+	path := filepath.Join(dir, "CHANGELOG.md")
+	args := []string{"-name", "1.2.0", "-project", "1337", "-file", path}
+
+	var outputs []string
+	for _, day := range []string{"2020-06-02", "2020-06-15"} {
+		a := testApp(t, server)
+		a.now = func() time.Time {
+			now, _ := time.Parse("2006-01-02", day)
+			return now
+		}
+
+		_, err = a.runChangelog(args)
+		assert.Nil(t, err)
+
+		content, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		outputs = append(outputs, string(content))
+	}
+
+	assert.Equal(t, outputs[0], outputs[1])
+	assert.Contains(t, outputs[0], "## [1.2.0] - 2020-06-01")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/config"
+	"github.com/marcelblijleven/version-meister/git"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/marcelblijleven/version-meister/review"
+	"os"
+	"strings"
+	"time"
+)
+
+func (a *app) runCreate(args []string) (*output.Result, error) {
+	options := cli.ParseCreateOptions(a.withDefaults(args))
+	result := &output.Result{Command: "create"}
+
+	date := options.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	name, err := a.versionName(options.ReleaseName, date, options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := jira.NewVersion(name, false, date, options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var commitsByKey map[string][]string
+	data := config.JQLData{Project: options.ProjectID, Version: version.Name, Component: options.Component, Status: releaseStatus}
+	// A create template of the profile decides itself how to use the component, only the default query gets the
+	// component clause
+	jql, custom, err := a.profile.RenderJQL("create", data)
+	if err != nil {
+		return nil, err
+	}
+	if !custom {
+		jql = fmt.Sprintf(jqlTemplate, options.ProjectID, jira.QuoteJQL(releaseStatus))
+	}
+
+	if options.UseGit() {
+		var keys []string
+		keys, commitsByKey, err = gitIssueKeys(options)
+		if err != nil {
+			return nil, err
+		}
+
+		project, err := client.GetProject(options.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		// JIRA rejects the whole search when one of the keys does not exist
+		keys = git.ProjectKeys(keys, project.Key)
+
+		if len(keys) == 0 {
+			result.Message = "No issue keys found in git history"
+			if options.FailOnEmpty {
+				return result, cli.ErrNoIssues
+			}
+			return result, nil
+		}
+
+		jql = fmt.Sprintf(jqlKeysTemplate, options.ProjectID, strings.Join(keys, ", "))
+		custom = false
+	}
+
+	if options.Component != "" && !custom {
+		jql += fmt.Sprintf(jqlComponentTemplate, jira.QuoteJQL(options.Component))
+	}
+
+	issues, err := client.Search(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) == 0 && options.FailOnEmpty {
+		return result, cli.ErrNoIssues
+	}
+
+	if options.Interactive {
+		selection, err := review.Review(os.Stdin, a.log, *version, issues)
+		if err == review.ErrAborted {
+			result.Message = "Review aborted, no changes were made"
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		version, issues = &selection.Version, selection.Issues
+	}
+
+	if options.DryRun {
+		result.Message = fmt.Sprintf("Dry run: version %v would be added to %v issue(s)", version.Name, len(issues))
+		result.Version = output.NewVersionResult(*version, false)
+		result.AddIssues(issues, "would assign")
+		return result, nil
+	}
+
+	var commentTemplate *release.CommentTemplate
+	var commentVisibility *jira.Visibility
+	if options.Comment {
+		if options.CommentVisibility != "" {
+			commentVisibility, err = jira.ParseVisibility(options.CommentVisibility)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if options.CommentTemplate != "" {
+			commentTemplate, err = release.CommentTemplateFromFile(options.CommentTemplate)
+		} else {
+			commentTemplate, err = release.NewCommentTemplate("")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := client.FindVersion(version.ProjectID, version.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.CreateVersion(*version); err != nil {
+		return nil, err
+	}
+	result.Version = output.NewVersionResult(*version, existing == nil)
+
+	// Creating the version is a change too, when it did not exist yet
+	created := 0
+	if existing == nil {
+		created = 1
+	}
+
+	for _, issue := range issues {
+		issueResult := output.NewIssueResult(issue, "assigned")
+
+		err = client.AddVersionToIssue(issue, *version)
+
+		if err == nil && commentTemplate != nil {
+			data := release.CommentData{
+				Issue:       issue.Key,
+				Version:     version.Name,
+				ReleaseDate: version.ReleaseDate,
+				BuildURL:    options.BuildURL,
+				Environment: options.Environment,
+				Commits:     commitsByKey[issue.Key],
+			}
+
+			err = a.postReleaseComment(client, issue, commentTemplate, commentVisibility, data)
+		}
+
+		if err != nil {
+			issueResult.Error = err.Error()
+			completed := created + len(result.Issues)
+			result.Issues = append(result.Issues, issueResult)
+			return result, cli.NewPartialError(err, completed, created+len(issues))
+		}
+
+		result.Issues = append(result.Issues, issueResult)
+	}
+
+	return result, nil
+}
+
+// postReleaseComment adds the rendered release comment to the issue, unless the issue already has the same comment
+func (a *app) postReleaseComment(client api.Commenter, issue jira.Issue, commentTemplate *release.CommentTemplate,
+	visibility *jira.Visibility, data release.CommentData) error {
+	comment, err := commentTemplate.Render(data)
+	if err != nil {
+		return err
+	}
+
+	comment.Visibility = visibility
+
+	existing, err := client.ListComments(issue)
+	if err != nil {
+		return err
+	}
+
+	if release.HasComment(existing, *comment) {
+		fmt.Fprintf(a.log, "Issue %v already has the release comment, skipping\n", issue.Key)
+		return nil
+	}
+
+	if err = client.AddCommentToIssue(issue, *comment); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.log, "Successfully added release comment to issue %v\n", issue.Key)
+	return nil
+}
+
+// gitCommits returns the commits from the provided git log file or from running git log between two refs
+func gitCommits(gitLog, repository, from, to string) ([]git.Commit, error) {
+	if gitLog == "" {
+		return git.Log(repository, from, to)
+	}
+
+	if gitLog == "-" {
+		return git.ParseLog(os.Stdin)
+	}
+
+	file, err := os.Open(gitLog)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return git.ParseLog(file)
+}
+
+// gitIssueKeys returns the unique issue keys referenced in the git history selected by the create options,
+// together with the SHAs of the commits that reference each key
+func gitIssueKeys(options cli.CreateOptions) ([]string, map[string][]string, error) {
+	extractor, err := git.NewExtractor(options.KeyPattern)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commits, err := gitCommits(options.GitLog, options.Repository, options.From, options.To)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return extractor.Keys(commits), extractor.CommitsByKey(commits), nil
+}
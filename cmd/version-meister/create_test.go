@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/config"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func testApp(t *testing.T, server *fakejira.Server) *app {
+	printer, err := output.New(output.FormatTable)
+	assert.Nil(t, err)
+
+	profile := &config.Profile{URL: server.URL, Username: "username", Password: "password"}
+	return &app{profile: profile, printer: printer, log: ioutil.Discard}
+}
+
+func TestCreateFailedFirstAssignmentCountsCreatedVersion(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	var issues []jira.Issue
+	for _, key := range []string{"AB-1", "AB-2"} {
+		issues = append(issues, server.AddIssue(jira.Issue{Key: key, Fields: &jira.IssueFields{
+			Project: jira.Project{ID: "1337"},
+			Status:  &jira.Status{Name: releaseStatus},
+		}}))
+	}
+	server.FailNext("PUT", "issue/"+issues[0].ID, http.StatusInternalServerError)
+
+	result, err := testApp(t, server).runCreate([]string{"-name", "1.2.0", "-project", "1337"})
+
+	var partial *cli.PartialError
+	assert.True(t, errors.As(err, &partial))
+	assert.Equal(t, 1, partial.Completed)
+	assert.Equal(t, 3, partial.Total)
+	assert.Equal(t, cli.ExitPartialFailure, cli.ExitCode(err))
+
+	assert.True(t, result.Version.Created)
+	assert.Len(t, result.Issues, 1)
+	assert.Len(t, server.Versions(1337), 1)
+}
+
+func TestCreateWithProfileTemplateDoesNotAppendComponent(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project:    jira.Project{ID: "1337"},
+		Status:     &jira.Status{Name: releaseStatus},
+		Components: []jira.Component{{Name: "backend"}},
+	}})
+	server.AddIssue(jira.Issue{Key: "AB-2", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Status:  &jira.Status{Name: releaseStatus},
+	}})
+
+	args := []string{"-name", "1.2.0", "-project", "1337", "-component", "backend", "-dryRun"}
+
+	// The default query is restricted to the component
+	a := testApp(t, server)
+	result, err := a.runCreate(args)
+	assert.Nil(t, err)
+	assert.Len(t, result.Issues, 1)
+
+	// The profile template ignores the component, so both issues match
+	a.profile.JQL = map[string]string{"create": `project = {{.Project}} AND status = "{{.Status}}"`}
+	result, err = a.runCreate(args)
+	assert.Nil(t, err)
+	assert.Len(t, result.Issues, 2)
+
+	a.profile.JQL = map[string]string{"create": `project = {{.Project}} AND component = "{{.Component}}"`}
+	result, err = a.runCreate(args)
+	assert.Nil(t, err)
+	assert.Len(t, result.Issues, 1)
+	assert.Equal(t, "AB-1", result.Issues[0].Key)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/config"
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/marcelblijleven/version-meister/output"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+// passphraseEnv can hold the passphrase of the encrypted credentials file, for non interactive use
+const passphraseEnv = "VERSION_MEISTER_PASSPHRASE"
+
+// credentialsProvider returns the credentials provider that is configured in the profile. When none is configured,
+// the encrypted credentials file and ~/.netrc are tried in that order
+func (a *app) credentialsProvider() (credentials.Provider, error) {
+	switch a.profile.Credentials {
+	case config.CredentialsEnv:
+		return credentials.Env{}, nil
+	case config.CredentialsNetrc:
+		return credentials.Netrc{}, nil
+	case config.CredentialsCommand:
+		if a.profile.CredentialsCommand == "" {
+			return nil, fmt.Errorf("Profile uses command credentials, but credentials_command is empty")
+		}
+		return credentials.Command{Username: a.profile.Username, Lookup: strings.Fields(a.profile.CredentialsCommand)}, nil
+	case config.CredentialsFile:
+		return a.credentialsFile("")
+	case "":
+		file, err := a.credentialsFile("")
+		if err != nil {
+			return nil, err
+		}
+		return credentials.Chain{file, credentials.Netrc{}}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown credentials source %v, expected one of %v, %v, %v or %v", a.profile.Credentials,
+		config.CredentialsEnv, config.CredentialsNetrc, config.CredentialsCommand, config.CredentialsFile)
+}
+
+// credentialsFile returns the encrypted credentials file at path, or the file of the profile when path is empty
+func (a *app) credentialsFile(path string) (credentials.EncryptedFile, error) {
+	if path == "" {
+		path = a.profile.CredentialsFile
+	}
+
+	if path == "" {
+		defaultPath, err := config.DefaultCredentialsFile()
+		if err != nil {
+			return credentials.EncryptedFile{}, err
+		}
+		path = defaultPath
+	}
+
+	return credentials.EncryptedFile{Path: path, Passphrase: a.passphrase}, nil
+}
+
+// passphrase returns the passphrase from the env or asks for it on the terminal
+func (a *app) passphrase() (string, error) {
+	if value := os.Getenv(passphraseEnv); value != "" {
+		return value, nil
+	}
+
+	return a.readSecret("Passphrase for the credentials file: ")
+}
+
+// readSecret reads a line from stdin, without echoing it when stdin is a terminal. Piped input is read with a
+// single reader, so a line that was buffered for an earlier secret is not lost
+func (a *app) readSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return strings.TrimSpace(string(secret)), err
+	}
+
+	if a.stdin == nil {
+		a.stdin = bufio.NewReader(os.Stdin)
+	}
+
+	line, err := a.stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func (a *app) runLogin(args []string) (*output.Result, error) {
+	options := cli.ParseLoginCommand(args)
+
+	host, err := credentials.Host(a.profile.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Could not determine the JIRA host, set url in the profile or JIRA_URL: %v", err)
+	}
+
+	username := options.Username
+	if username == "" {
+		username = a.profile.Username
+	}
+
+	var store credentials.Store
+	if options.Store == "command" {
+		command := options.Command
+		if command == "" {
+			command = a.profile.CredentialsStoreCommand
+		}
+		if command == "" {
+			return nil, fmt.Errorf("Provide -command or credentials_store_command in the profile")
+		}
+		store = credentials.Command{Save: strings.Fields(command)}
+	} else {
+		if store, err = a.credentialsFile(options.File); err != nil {
+			return nil, err
+		}
+	}
+
+	secret, err := a.readSecret(fmt.Sprintf("API token or password for %v: ", host))
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("Secret cannot be empty")
+	}
+
+	creds := credentials.Credentials{Username: username, Password: secret}
+	if username == "" {
+		creds = credentials.Credentials{Token: secret}
+	}
+
+	if err = store.Store(host, creds); err != nil {
+		return nil, err
+	}
+
+	return &output.Result{Command: "login", Message: fmt.Sprintf("Successfully stored credentials for %v", host)}, nil
+}
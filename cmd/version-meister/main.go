@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/audit"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/config"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/metrics"
+	"github.com/marcelblijleven/version-meister/output"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// The status, component and version are quoted with jira.QuoteJQL
+	jqlTemplate          = "project = %v AND status = %v AND fixVersion IS EMPTY"
+	jqlComponentTemplate = " AND component = %v"
+	jqlVersionTemplate   = "project = %v AND fixVersion = %v"
+	jqlKeysTemplate      = "project = %v AND key in (%v)"
+	releaseStatus        = "Ready for Release"
+	metricsJob           = "version_meister"
+)
+
+const usage = `Usage: version-meister [-profile name] [-config file] <command> [flags]
+
+Commands:
+  create    Create a version and assign it to issues that are ready for release
+  notes     Render release notes for the issues in a version
+  changelog Add the issues in a version to a Keep a Changelog CHANGELOG.md
+  reconcile Report differences between git history and the issues in a version
+  run       Plan or apply the release steps in a release.yaml file
+  rollback  Undo the changes that a run made
+  login     Store an API token or password in the encrypted credentials file or a password manager
+  audit     Show the changes that were made to JIRA, from the audit log
+  serve     Run a webhook server that assigns versions to issues on JIRA events
+
+Every change made to JIRA is appended to version-meister/audit.jsonl in $XDG_STATE_HOME, or ~/.local/state when
+it is not set, use -auditLog to change or disable it.
+
+Global flags:
+`
+
+// app holds the settings that are shared by all commands
+type app struct {
+	profile *config.Profile
+	printer *output.Printer
+	// log receives progress messages, it is stderr when the output is structured so stdout only has the result
+	log io.Writer
+	// verbose logs every request to stderr
+	verbose bool
+	// retry retries rate limited requests and requests that failed because JIRA was unavailable
+	retry bool
+	// metrics records the JIRA requests when a metrics file or Pushgateway is set, it is nil otherwise
+	metrics *metrics.JiraMetrics
+	// audit records every change made to JIRA, it is nil when the audit log is disabled
+	audit     *audit.Log
+	auditPath string
+	// stdin reads the secrets that are piped to the command
+	stdin *bufio.Reader
+	// now returns the current time, it is time.Now when nil
+	now func() time.Time
+}
+
+func main() {
+	global := flag.NewFlagSet("version-meister", flag.ExitOnError)
+	profileName := global.String("profile", os.Getenv("VERSION_MEISTER_PROFILE"), "Name of the config profile to use")
+	configPath := global.String("config", "", "Optional config file to use instead of the default config files")
+	outputFormat := global.String("output", output.FormatTable, "Output format: table, json, yaml, csv or template=<Go template>")
+	verbose := global.Bool("verbose", false, "Log every JIRA request with its request ID to stderr")
+	retry := global.Bool("retry", false, "Retry requests that JIRA rate limited (429) or that failed because JIRA was unavailable (503)")
+	metricsFile := global.String("metricsFile", "", "Optional file to write metrics to for the node exporter textfile collector, e.g. /var/lib/node_exporter/version_meister.prom")
+	metricsPush := global.String("metricsPush", "", "Optional Pushgateway url to push metrics to, e.g. http://localhost:9091")
+	// Without a home directory there is no default, the audit log is disabled unless the flag is set
+	defaultAuditLog, _ := audit.DefaultPath()
+	auditLog := global.String("auditLog", defaultAuditLog, "File that every change made to JIRA is appended to, empty disables the audit log")
+	global.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		global.PrintDefaults()
+	}
+
+	global.Parse(os.Args[1:])
+	args := global.Args()
+
+	if len(args) < 1 {
+		global.Usage()
+		os.Exit(cli.ExitUsage)
+	}
+
+	printer, err := output.New(*outputFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitUsage)
+	}
+
+	a, err := newApp(*profileName, *configPath, printer)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	a.verbose = *verbose
+	a.retry = *retry || a.profile.Retry
+	a.auditPath = *auditLog
+	if *auditLog != "" {
+		runID, err := audit.NewRunID()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		a.audit = audit.New(*auditLog)
+		a.audit.SetRunID(runID)
+	}
+
+	var registry *metrics.Registry
+	if *metricsFile != "" || *metricsPush != "" {
+		registry = metrics.NewRegistry()
+		a.metrics = metrics.NewJiraMetrics(registry)
+	}
+
+	var result *output.Result
+	switch args[0] {
+	case "create":
+		result, err = a.runCreate(args[1:])
+	case "notes":
+		result, err = a.runNotes(args[1:])
+	case "changelog":
+		result, err = a.runChangelog(args[1:])
+	case "reconcile":
+		result, err = a.runReconcile(args[1:])
+	case "run":
+		result, err = a.runRun(args[1:])
+	case "rollback":
+		result, err = a.runRollback(args[1:])
+	case "login":
+		result, err = a.runLogin(args[1:])
+	case "audit":
+		result, err = a.runAudit(args[1:])
+	case "serve":
+		result, err = a.runServe(args[1:])
+	default:
+		global.Usage()
+		os.Exit(cli.ExitUsage)
+	}
+
+	if err != nil && printer.Structured() {
+		if result == nil {
+			result = &output.Result{Command: args[0]}
+		}
+		result.AddError(err)
+	}
+
+	if result != nil {
+		if printErr := printer.Print(os.Stdout, result); printErr != nil && err == nil {
+			err = printErr
+		}
+	}
+
+	if registry != nil {
+		// Metrics are exported after failures too, failing to export them does not fail the command
+		if exportErr := exportMetrics(registry, *metricsFile, *metricsPush); exportErr != nil {
+			fmt.Fprintln(os.Stderr, "Could not export metrics:", exportErr)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitCode(err))
+	}
+}
+
+// exportMetrics writes the metrics to the file and pushes them to the Pushgateway, when they are set
+func exportMetrics(registry *metrics.Registry, file, pushURL string) error {
+	if file != "" {
+		if err := registry.WriteFile(file); err != nil {
+			return err
+		}
+	}
+
+	if pushURL != "" {
+		return registry.Push(pushURL, metricsJob)
+	}
+
+	return nil
+}
+
+// newApp loads the config files and selects the profile. Settings are applied in this order, later ones win:
+// the user config file, the repository local config file, env variables and finally command flags
+func newApp(profileName, configPath string, printer *output.Printer) (*app, error) {
+	paths := config.DefaultPaths()
+	if configPath != "" {
+		paths = []string{configPath}
+	}
+
+	cfg, err := config.Load(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := cfg.Profile(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.ApplyEnv(os.Getenv)
+
+	var log io.Writer = os.Stdout
+	if printer.Structured() {
+		log = os.Stderr
+	}
+
+	return &app{profile: profile, printer: printer, log: log}, nil
+}
+
+// newClient returns an api client for the selected profile. When the profile has no password or token,
+// the credentials are read from the configured credentials provider
+func (a *app) newClient() (api.JiraAPI, error) {
+	method, err := a.profile.AuthMethod()
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.profile.HasSecret() {
+		provider, err := a.credentialsProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := api.NewClientWithProvider(a.profile.URL, provider)
+		if err != nil {
+			return nil, err
+		}
+
+		return a.configureClient(client)
+	}
+
+	var client *api.Client
+	if method == config.AuthBearer {
+		client, err = api.NewTokenClient(a.profile.URL, a.profile.Token)
+	} else {
+		client, err = api.NewClient(a.profile.URL, a.profile.Username, a.profile.Password)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.configureClient(client)
+}
+
+// configureClient applies the client settings of the profile. The client is returned as api.JiraAPI, the commands
+// only depend on that interface
+func (a *app) configureClient(client *api.Client) (api.JiraAPI, error) {
+	client.SetOutput(a.log)
+	if a.audit != nil {
+		client.SetAuditLog(a.audit)
+	}
+
+	if a.retry {
+		retry := api.RetryOptions{}
+		if a.metrics != nil {
+			retry.OnRetry = a.metrics.OnRetry
+		}
+		client.Use(api.Retry(retry))
+	}
+
+	if a.verbose {
+		client.Use(api.RequestID(), api.Logging(os.Stderr))
+	}
+	if a.metrics != nil {
+		client.Use(a.metrics.Middleware())
+	}
+
+	apiVersion, err := api.ParseAPIVersion(a.profile.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.SetAPIVersion(apiVersion); err != nil {
+		return nil, err
+	}
+
+	if a.metrics != nil {
+		return a.metrics.Instrument(client), nil
+	}
+
+	return client, nil
+}
+
+// today returns the current date in the format of release dates
+func (a *app) today() string {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	return now().Format("2006-01-02")
+}
+
+// withDefaults prepends the defaults of the profile to the command args, flags that are provided by the user
+// come later and therefore override the defaults
+func (a *app) withDefaults(args []string) []string {
+	if a.profile.Project == 0 {
+		return args
+	}
+
+	return append([]string{"-project", strconv.Itoa(a.profile.Project)}, args...)
+}
+
+// versionName applies the version naming convention of the profile to the name
+func (a *app) versionName(name, date string, projectID int) (string, error) {
+	return a.profile.RenderVersionName(config.NameData{Name: name, Date: date, Project: projectID})
+}
+
+// jql returns the named JQL query from the profile, or the fallback when the profile does not define it
+func (a *app) jql(name string, data config.JQLData, fallback string) (string, error) {
+	jql, ok, err := a.profile.RenderJQL(name, data)
+	if err != nil || !ok {
+		return fallback, err
+	}
+
+	return jql, nil
+}
+
+// versionJQL returns the query for all issues in a version
+func (a *app) versionJQL(projectID int, version string) (string, error) {
+	data := config.JQLData{Project: projectID, Version: version}
+	return a.jql("version", data, fmt.Sprintf(jqlVersionTemplate, projectID, jira.QuoteJQL(version)))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/notes"
+	"github.com/marcelblijleven/version-meister/output"
+	"io"
+	"os"
+	"text/template"
+)
+
+func (a *app) runNotes(args []string) (*output.Result, error) {
+	options := cli.ParseNotesCommand(a.withDefaults(args))
+
+	name, err := a.versionName(options.ReleaseName, "", options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	jql, err := a.versionJQL(options.ProjectID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := client.Search(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	// The release date of the version is shown in the notes, a version that does not exist has none
+	version := jira.Version{Name: name, ProjectID: options.ProjectID}
+	existing, err := client.FindVersion(options.ProjectID, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		version = *existing
+	}
+
+	releaseNotes, err := notes.NewReleaseNotes(name, version.ReleaseDate, a.profile.URL, options.GroupBy, issues)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpl *template.Template
+	if options.TemplatePath != "" {
+		tmpl, err = notes.TemplateFromFile(options.TemplatePath)
+	} else {
+		tmpl, err = notes.Template(options.Format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Structured output replaces the release notes on stdout, they are only written to the -out file
+	var result *output.Result
+	if a.printer.Structured() {
+		result = &output.Result{Command: "notes", Version: output.NewVersionResult(version, false)}
+		result.AddIssues(issues, "included")
+
+		if options.OutputPath == "" {
+			return result, nil
+		}
+	}
+
+	var writer io.Writer = os.Stdout
+	if options.OutputPath != "" {
+		file, err := os.Create(options.OutputPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	return result, notes.Render(writer, tmpl, releaseNotes)
+}
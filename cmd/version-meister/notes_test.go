@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotesUseReleaseDateAndQuotedVersionName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notes")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	version := server.AddVersion(jira.Version{Name: `Release "Phoenix"`, ReleaseDate: "2020-06-01", ProjectID: 1337})
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project:     jira.Project{ID: "1337"},
+		Summary:     "Fix crash on startup",
+		IssueType:   &jira.IssueType{Name: "Bug"},
+		FixVersions: []jira.Version{version},
+	}})
+
+	path := filepath.Join(dir, "notes.md")
+	args := []string{"-name", `Release "Phoenix"`, "-project", "1337", "-out", path}
+	_, err = testApp(t, server).runNotes(args)
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `# Release notes Release "Phoenix" (2020-06-01)`)
+	assert.Contains(t, string(content), "AB-1")
+}
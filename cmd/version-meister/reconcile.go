@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/git"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/marcelblijleven/version-meister/reconcile"
+	"os"
+)
+
+func (a *app) runReconcile(args []string) (*output.Result, error) {
+	options := cli.ParseReconcileCommand(a.withDefaults(args))
+
+	name, err := a.versionName(options.ReleaseName, "", options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := git.NewExtractor(options.KeyPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := gitCommits(options.GitLog, options.Repository, options.From, options.To)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	jql, err := a.versionJQL(options.ProjectID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := client.Search(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := client.GetProject(options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys of other projects, and text like UTF-8 that matches the key pattern, are not part of the version
+	keys := git.ProjectKeys(extractor.Keys(commits), project.Key)
+
+	lookup := func(key string) (*jira.Issue, error) {
+		issue, err := client.GetIssue(key)
+		if err == api.ErrIssueNotFound {
+			return nil, nil
+		}
+		return issue, err
+	}
+
+	report, err := reconcile.Compare(name, keys, issues, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	result := reconcileResult(report, options.ProjectID)
+	if !a.printer.Structured() {
+		// The report is more readable than the result table
+		if err = report.Write(os.Stdout); err != nil {
+			return nil, err
+		}
+		result = nil
+	}
+
+	if !options.Fix {
+		return result, nil
+	}
+
+	version := jira.Version{Name: name, ProjectID: options.ProjectID}
+	err = report.Fix(func(issue jira.Issue) error {
+		return client.AddVersionToIssue(issue, version)
+	})
+
+	if err == nil && result != nil {
+		for i := range result.Issues {
+			if result.Issues[i].Action == actionMissingInJira {
+				result.Issues[i].Action = "fixed"
+			}
+		}
+	}
+
+	return result, err
+}
+
+// Actions in the reconcile result
+const (
+	actionMissingInJira = "missing in jira"
+	actionMissingInGit  = "missing in git"
+	actionUnknown       = "unknown"
+)
+
+func reconcileResult(report *reconcile.Report, projectID int) *output.Result {
+	result := &output.Result{
+		Command: "reconcile",
+		Message: fmt.Sprintf("Version %v matches git history", report.Version),
+		Version: output.NewVersionResult(jira.Version{Name: report.Version, ProjectID: projectID}, false),
+	}
+
+	if report.HasDrift() {
+		result.Message = fmt.Sprintf("Version %v does not match git history", report.Version)
+	}
+
+	result.AddIssues(report.MissingInJira, actionMissingInJira)
+	result.AddIssues(report.MissingInGit, actionMissingInGit)
+	for _, key := range report.Unknown {
+		result.Issues = append(result.Issues, output.IssueResult{Key: key, Action: actionUnknown})
+	}
+
+	return result
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const reconcileLog = `commit 9fceb02d0ae598e95dc970b74767f19372d61af8
+Author: Test User <test@example.com>
+Date:   Thu Jan 16 10:00:00 2020 +0100
+
+    AB-1 Read config files as UTF-8
+
+commit 2f8d1d2b1a1c8c6c0a9ec8b1f3c56a70a7e2b2f3
+Author: Test User <test@example.com>
+Date:   Wed Jan 15 10:00:00 2020 +0100
+
+    Bump CD-10 checksums to SHA-256
+`
+
+func TestReconcileIgnoresKeysOfOtherProjects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reconcile")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	gitLog := filepath.Join(dir, "git.log")
+	assert.Nil(t, ioutil.WriteFile(gitLog, []byte(reconcileLog), 0600))
+
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+
+	a := testApp(t, server)
+	a.printer, err = output.New(output.FormatJSON)
+	assert.Nil(t, err)
+
+	result, err := a.runReconcile([]string{"-name", "1.2.0", "-project", "1337", "-gitLog", gitLog})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Issues, 1)
+	assert.Equal(t, "AB-1", result.Issues[0].Key)
+	assert.Equal(t, actionMissingInJira, result.Issues[0].Action)
+	assert.NotContains(t, server.Requests(), "GET issue/UTF-8")
+	assert.NotContains(t, server.Requests(), "GET issue/SHA-256")
+	assert.NotContains(t, server.Requests(), "GET issue/CD-10")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/marcelblijleven/version-meister/release"
+)
+
+func (a *app) runRollback(args []string) (*output.Result, error) {
+	options := cli.ParseRollbackCommand(a.withDefaults(args))
+
+	runID := options.RunID
+	if runID == "" {
+		runID = journal.DefaultRunID(options.ProjectID, options.ReleaseName)
+	}
+
+	runJournal, err := journal.Open(options.Journal, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.audit != nil {
+		// Changes are recorded with the ID of the run, so the audit log and the journal can be matched
+		a.audit.SetRunID(runID)
+	}
+
+	if len(runJournal.Entries) == 0 {
+		return nil, fmt.Errorf("No journal found for run %v in %v", runID, options.Journal)
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	runner := release.NewRunner(client, a.profile.URL)
+	runner.SetJournal(runJournal)
+	runner.SetOutput(a.log)
+
+	before := runJournal.Completed()
+	err = runner.Rollback(options.DryRun)
+	fmt.Fprintln(a.log)
+	if writeErr := runJournal.Write(a.log); writeErr != nil && err == nil {
+		err = writeErr
+	}
+
+	if !a.printer.Structured() {
+		return nil, err
+	}
+
+	result := &output.Result{
+		Command: "rollback",
+		Version: output.NewVersionResult(jira.Version{Name: runJournal.Version}, false),
+	}
+
+	for _, entry := range before {
+		if entry.Issue == "" || (!options.DryRun && runJournal.Done(entry.Step)) {
+			continue
+		}
+
+		action := "rolled back " + entry.Action
+		if options.DryRun {
+			action = "would roll back " + entry.Action
+		}
+		result.Issues = append(result.Issues, output.IssueResult{Key: entry.Issue, Action: action})
+	}
+
+	return result, err
+}
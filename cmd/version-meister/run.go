@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/marcelblijleven/version-meister/release"
+	"os"
+)
+
+func (a *app) runRun(args []string) (*output.Result, error) {
+	options := cli.ParseRunCommand(a.withDefaults(args))
+
+	file, err := release.LoadFile(options.File, release.VariableLookup(options.Vars, os.LookupEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Version.Project == 0 {
+		file.Version.Project = options.ProjectID
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	runID := options.RunID
+	if runID == "" {
+		runID = journal.DefaultRunID(file.Version.Project, file.Version.Name)
+	}
+
+	runJournal, err := journal.Open(options.Journal, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.audit != nil {
+		// Changes are recorded with the ID of the run, so the audit log and the journal can be matched
+		a.audit.SetRunID(runID)
+	}
+
+	runner := release.NewRunner(client, a.profile.URL)
+	runner.SetJournal(runJournal)
+	runner.SetOutput(a.log)
+	runner.SetConcurrency(options.Concurrency)
+
+	plan, err := runner.Plan(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = plan.Write(a.log); err != nil {
+		return nil, err
+	}
+
+	if len(plan.Issues) == 0 && options.FailOnEmpty {
+		return a.runResult(plan, nil), cli.ErrNoIssues
+	}
+
+	if options.Mode != cli.RunApply {
+		return a.runResult(plan, nil), nil
+	}
+
+	completed := len(runJournal.Entries)
+	err = runner.Apply(plan)
+	err = cli.NewPartialError(err, len(runJournal.Entries)-completed, len(plan.Steps))
+	fmt.Fprintln(a.log)
+	if writeErr := runJournal.Write(a.log); writeErr != nil && err == nil {
+		err = writeErr
+	}
+
+	return a.runResult(plan, runJournal), err
+}
+
+// runResult returns the structured result of the plan, steps that are not in the journal are marked as not applied.
+// Without structured output the plan and journal summary are the result, so nil is returned
+func (a *app) runResult(plan *release.Plan, runJournal *journal.Journal) *output.Result {
+	if !a.printer.Structured() {
+		return nil
+	}
+
+	result := &output.Result{
+		Command: "run",
+		Message: fmt.Sprintf("Plan for version %v has %v step(s)", plan.Version.Name, len(plan.Steps)),
+	}
+
+	created := false
+	for _, step := range plan.Steps {
+		applied := runJournal != nil && runJournal.Done(step.String())
+		if step.Action == release.ActionCreateVersion && applied {
+			created = true
+		}
+
+		if step.Issue == nil {
+			continue
+		}
+
+		issueResult := output.NewIssueResult(*step.Issue, step.Action)
+		if runJournal != nil && !applied {
+			issueResult.Error = "not applied"
+		}
+		result.Issues = append(result.Issues, issueResult)
+	}
+
+	result.Version = output.NewVersionResult(plan.Version, created)
+	return result
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/cli"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/marcelblijleven/version-meister/webhook"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the server waits for requests that are being received when it is stopped
+const shutdownTimeout = 30 * time.Second
+
+// runServe runs the webhook server until it receives SIGINT or SIGTERM
+func (a *app) runServe(args []string) (*output.Result, error) {
+	options := cli.ParseServeCommand(args)
+
+	rules, err := webhook.LoadRules(options.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := webhook.NewHandler(client, options.Secret, rules)
+	if err != nil {
+		return nil, err
+	}
+	handler.SetOutput(a.log)
+
+	mux := http.NewServeMux()
+	mux.Handle(options.Path, handler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	server := &http.Server{Addr: options.Address, Handler: mux, ReadTimeout: 10 * time.Second}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	done := make(chan error, 1)
+	go func() {
+		<-stop
+		fmt.Fprintln(a.log, "Stopping webhook server")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		done <- server.Shutdown(ctx)
+	}()
+
+	fmt.Fprintf(a.log, "Listening for webhooks on %v%v with %v rule(s)\n", options.Address, options.Path, len(rules))
+	if err = server.ListenAndServe(); err != http.ErrServerClosed {
+		handler.Close()
+		return nil, err
+	}
+
+	err = <-done
+	// The events that were accepted are still handled, JIRA does not deliver them again
+	handler.Close()
+	return nil, err
+}
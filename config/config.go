@@ -0,0 +1,313 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Credential sources for a profile
+const (
+	CredentialsEnv     = "env"
+	CredentialsNetrc   = "netrc"
+	CredentialsCommand = "command"
+	CredentialsFile    = "file"
+)
+
+// Authentication methods for a profile
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// LocalFile is the name of the repository local config file, it is read from the working directory. Anyone who can
+// commit to a repository controls it, so it cannot set the JIRA url or where credentials come from, see Load
+const LocalFile = ".version-meister.yaml"
+
+// Config holds the named profiles from one or more config files
+type Config struct {
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]*Profile `yaml:"profiles"`
+}
+
+// Profile holds the settings for a single JIRA instance
+type Profile struct {
+	URL        string `yaml:"url"`
+	Auth       string `yaml:"auth"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	Token      string `yaml:"token"`
+	APIVersion string `yaml:"api_version"`
+	Project    int    `yaml:"project"`
+	// JQL holds named JQL templates, e.g. create and version, see JQLData for the available fields
+	JQL map[string]string `yaml:"jql"`
+	// VersionName is a template for version names, e.g. "backend-{{.Name}}", see NameData for the available fields
+	VersionName string `yaml:"version_name"`
+	// Credentials selects where the credentials are read from when no password or token is configured:
+	// env, netrc, command or file
+	Credentials string `yaml:"credentials"`
+	// CredentialsCommand prints the secret, e.g. "pass show jira/{host}"
+	CredentialsCommand string `yaml:"credentials_command"`
+	// CredentialsStoreCommand stores the secret it reads from stdin, e.g. "pass insert -m jira/{host}"
+	CredentialsStoreCommand string `yaml:"credentials_store_command"`
+	// CredentialsFile is the age encrypted credentials file, see DefaultCredentialsFile
+	CredentialsFile string `yaml:"credentials_file"`
+	// Retry retries requests that JIRA rate limited or that failed because JIRA was unavailable
+	Retry bool `yaml:"retry"`
+}
+
+// JQLData contains the fields that are available in JQL templates
+type JQLData struct {
+	Project   int
+	Version   string
+	Component string
+	Status    string
+}
+
+// NameData contains the fields that are available in the version name template
+type NameData struct {
+	Name    string
+	Date    string
+	Project int
+}
+
+// DefaultPaths returns the config files in the order they are loaded: the user config file followed by the
+// repository local file
+func DefaultPaths() []string {
+	var paths []string
+
+	if dir, err := userConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "version-meister", "config.yaml"))
+	}
+
+	return append(paths, LocalFile)
+}
+
+// DefaultCredentialsFile returns the path of the encrypted credentials file in the user config directory
+func DefaultCredentialsFile() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "version-meister", "credentials.age"), nil
+}
+
+func userConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config"), nil
+}
+
+// Load reads the config files at paths, files that do not exist are skipped. Settings in later files
+// override the settings of the same profile in earlier files. A LocalFile that sets the url or a credentials
+// setting returns an error, those would send credentials to another host or run commands from the repository
+func Load(paths ...string) (*Config, error) {
+	config := Config{Profiles: make(map[string]*Profile)}
+
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var file Config
+		if err = yaml.UnmarshalStrict(content, &file); err != nil {
+			return nil, fmt.Errorf("Could not read config file %v: %v", path, err)
+		}
+
+		if filepath.Base(path) == LocalFile {
+			if err = file.checkLocal(); err != nil {
+				return nil, fmt.Errorf("Could not read config file %v: %v", path, err)
+			}
+		}
+
+		config.merge(file)
+	}
+
+	return &config, nil
+}
+
+// checkLocal returns an error when a profile sets one of the settings that only the user config file may set
+func (c *Config) checkLocal() error {
+	for name, profile := range c.Profiles {
+		if profile == nil {
+			continue
+		}
+
+		restricted := []struct{ key, value string }{
+			{"url", profile.URL},
+			{"credentials", profile.Credentials},
+			{"credentials_command", profile.CredentialsCommand},
+			{"credentials_store_command", profile.CredentialsStoreCommand},
+		}
+		for _, setting := range restricted {
+			if setting.value != "" {
+				return fmt.Errorf("Profile %v sets %v, which is only allowed in the user config file", name, setting.key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) merge(other Config) {
+	if other.DefaultProfile != "" {
+		c.DefaultProfile = other.DefaultProfile
+	}
+
+	for name, profile := range other.Profiles {
+		if profile == nil {
+			continue
+		}
+
+		existing, ok := c.Profiles[name]
+		if !ok {
+			existing = &Profile{}
+			c.Profiles[name] = existing
+		}
+
+		existing.merge(*profile)
+	}
+}
+
+func (p *Profile) merge(other Profile) {
+	overrideString(&p.URL, other.URL)
+	overrideString(&p.Auth, other.Auth)
+	overrideString(&p.Username, other.Username)
+	overrideString(&p.Password, other.Password)
+	overrideString(&p.Token, other.Token)
+	overrideString(&p.APIVersion, other.APIVersion)
+	overrideString(&p.VersionName, other.VersionName)
+	overrideString(&p.Credentials, other.Credentials)
+	overrideString(&p.CredentialsCommand, other.CredentialsCommand)
+	overrideString(&p.CredentialsStoreCommand, other.CredentialsStoreCommand)
+	overrideString(&p.CredentialsFile, other.CredentialsFile)
+
+	if other.Project != 0 {
+		p.Project = other.Project
+	}
+
+	if other.Retry {
+		p.Retry = true
+	}
+
+	for name, jql := range other.JQL {
+		if p.JQL == nil {
+			p.JQL = make(map[string]string)
+		}
+		p.JQL[name] = jql
+	}
+}
+
+func overrideString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+// Profile returns the profile with the provided name, the default profile is used when the name is empty.
+// An empty profile is returned when no name is provided and there is no default profile
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+
+	if name == "" {
+		return &Profile{}, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("Profile %v does not exist", name)
+	}
+
+	copied := *profile
+	return &copied, nil
+}
+
+// ApplyEnv overrides the profile with the JIRA_URL, JIRA_USERNAME, JIRA_PASSWORD, JIRA_TOKEN and JIRA_API_VERSION
+// env variables, using getenv to read them
+func (p *Profile) ApplyEnv(getenv func(string) string) {
+	overrideString(&p.URL, getenv("JIRA_URL"))
+	overrideString(&p.Username, getenv("JIRA_USERNAME"))
+	overrideString(&p.Password, getenv("JIRA_PASSWORD"))
+	overrideString(&p.APIVersion, getenv("JIRA_API_VERSION"))
+
+	if token := getenv("JIRA_TOKEN"); token != "" {
+		p.Token = token
+		if p.Auth == "" {
+			p.Auth = AuthBearer
+		}
+	}
+}
+
+// HasSecret reports if the profile contains the password or token for its auth method
+func (p *Profile) HasSecret() bool {
+	if p.Auth == AuthBearer {
+		return p.Token != ""
+	}
+	return p.Password != ""
+}
+
+// AuthMethod returns the authentication method of the profile, basic is used when none is configured
+func (p *Profile) AuthMethod() (string, error) {
+	switch p.Auth {
+	case "", AuthBasic:
+		return AuthBasic, nil
+	case AuthBearer:
+		return AuthBearer, nil
+	}
+
+	return "", fmt.Errorf("Unknown auth method %v, expected %v or %v", p.Auth, AuthBasic, AuthBearer)
+}
+
+// RenderJQL renders the named JQL template of the profile, ok is false when the profile has no such template
+func (p *Profile) RenderJQL(name string, data JQLData) (jql string, ok bool, err error) {
+	text, ok := p.JQL[name]
+	if !ok {
+		return "", false, nil
+	}
+
+	jql, err = render(name, text, data)
+	return jql, true, err
+}
+
+// RenderVersionName applies the version name template of the profile, the name is returned as is when the
+// profile has no template
+func (p *Profile) RenderVersionName(data NameData) (string, error) {
+	if p.VersionName == "" {
+		return data.Name, nil
+	}
+
+	return render("version_name", p.VersionName, data)
+}
+
+func render(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err = tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
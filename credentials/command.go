@@ -0,0 +1,98 @@
+package credentials
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// hostPlaceholder is replaced by the JIRA host in command arguments
+const hostPlaceholder = "{host}"
+
+// usernamePrefix starts the line with the username after the secret, the format that pass uses for extra fields
+const usernamePrefix = "username:"
+
+// Command is a Provider and Store that uses an external password manager, like pass or secret-tool.
+// The secret is read from the first line of the output of Lookup and written to the standard input of Save.
+// A username is stored on a "username: me@example.com" line after the secret. The {host} placeholder in the arguments is replaced by the JIRA host, for example:
+//
+//	Lookup: []string{"secret-tool", "lookup", "service", "version-meister", "host", "{host}"}
+//	Save:   []string{"secret-tool", "store", "--label=version-meister", "service", "version-meister", "host", "{host}"}
+type Command struct {
+	// Username is used together with the secret as password. When it is empty the username line of the output is
+	// used, and without one the secret is used as token
+	Username string
+	Lookup   []string
+	Save     []string
+}
+
+// Credentials runs the Lookup command, ErrNotFound is returned when it prints nothing
+func (c Command) Credentials(host string) (*Credentials, error) {
+	if len(c.Lookup) == 0 {
+		return nil, fmt.Errorf("Lookup command cannot be empty")
+	}
+
+	args := replaceHost(c.Lookup, host)
+	cmd := exec.Command(args[0], args[1:]...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+
+	if err != nil {
+		return nil, fmt.Errorf("%v failed: %v %v", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	lines := strings.Split(string(output), "\n")
+	secret := strings.TrimSpace(lines[0])
+	if secret == "" {
+		return nil, ErrNotFound
+	}
+
+	username := c.Username
+	for _, line := range lines[1:] {
+		if username == "" && strings.HasPrefix(strings.ToLower(line), usernamePrefix) {
+			username = strings.TrimSpace(line[len(usernamePrefix):])
+		}
+	}
+
+	if username == "" {
+		return &Credentials{Token: secret}, nil
+	}
+
+	return &Credentials{Username: username, Password: secret}, nil
+}
+
+// Store runs the Save command with the secret, and the username line when there is a username, on its standard input
+func (c Command) Store(host string, credentials Credentials) error {
+	if len(c.Save) == 0 {
+		return fmt.Errorf("Save command cannot be empty")
+	}
+
+	input := credentials.Token + "\n"
+	if credentials.Password != "" {
+		input = credentials.Password + "\n"
+		if credentials.Username != "" {
+			input += fmt.Sprintf("%v %v\n", usernamePrefix, credentials.Username)
+		}
+	}
+
+	args := replaceHost(c.Save, host)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v failed: %v %v", args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func replaceHost(args []string, host string) []string {
+	replaced := make([]string, len(args))
+	for i, arg := range args {
+		replaced[i] = strings.Replace(arg, hostPlaceholder, host, -1)
+	}
+	return replaced
+}
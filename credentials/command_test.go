@@ -0,0 +1,93 @@
+package credentials_test
+
+import (
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandCredentials(t *testing.T) {
+	command := credentials.Command{
+		Username: "me",
+		Lookup:   []string{"sh", "-c", "printf 'secret-for-%s\\nsecond line\\n' \"$0\"", "{host}"},
+	}
+
+	result, err := command.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Username: "me", Password: "secret-for-jira.example.com"}, result)
+}
+
+func TestCommandCredentialsWithoutUsernameReturnsToken(t *testing.T) {
+	command := credentials.Command{Lookup: []string{"echo", "token"}}
+	result, err := command.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Token: "token"}, result)
+}
+
+func TestCommandCredentialsWithUsernameLine(t *testing.T) {
+	command := credentials.Command{Lookup: []string{"printf", "secret\\nusername: me@example.com\\n"}}
+	result, err := command.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Username: "me@example.com", Password: "secret"}, result)
+}
+
+func TestCommandCredentialsEmptyOutput(t *testing.T) {
+	command := credentials.Command{Lookup: []string{"true"}}
+	result, err := command.Credentials("jira.example.com")
+
+	assert.Equal(t, credentials.ErrNotFound, err)
+	assert.Nil(t, result)
+}
+
+func TestCommandCredentialsFailure(t *testing.T) {
+	command := credentials.Command{Lookup: []string{"false"}}
+	result, err := command.Credentials("jira.example.com")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, result)
+}
+
+func TestCommandStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secret")
+	command := credentials.Command{Save: []string{"sh", "-c", "cat > " + path}}
+
+	err = command.Store("jira.example.com", credentials.Credentials{Token: "token"})
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "token\n", string(content))
+}
+
+func TestCommandStoreWithUsername(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secret")
+	command := credentials.Command{
+		Save:   []string{"sh", "-c", "cat > " + path},
+		Lookup: []string{"cat", path},
+	}
+
+	stored := credentials.Credentials{Username: "me@example.com", Password: "secret"}
+	assert.Nil(t, command.Store("jira.example.com", stored))
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret\nusername: me@example.com\n", string(content))
+
+	result, err := command.Credentials("jira.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, &stored, result)
+}
@@ -0,0 +1,64 @@
+package credentials
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrNotFound is returned by a Provider that has no credentials for the requested host
+var ErrNotFound = errors.New("No credentials found")
+
+// Credentials hold the secrets used to authenticate with JIRA. JIRA Cloud uses the email address as Username and an
+// API token as Password, JIRA Server and Data Center use a username and password, or only a personal access Token
+type Credentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// Provider returns the credentials for a JIRA host
+type Provider interface {
+	Credentials(host string) (*Credentials, error)
+}
+
+// Store saves the credentials for a JIRA host
+type Store interface {
+	Store(host string, credentials Credentials) error
+}
+
+// Chain is a Provider that returns the credentials of the first provider that has credentials for the host
+type Chain []Provider
+
+// Credentials returns the credentials of the first provider that does not return ErrNotFound
+func (c Chain) Credentials(host string) (*Credentials, error) {
+	for _, provider := range c {
+		credentials, err := provider.Credentials(host)
+
+		if err == ErrNotFound {
+			continue
+		}
+
+		return credentials, err
+	}
+
+	return nil, ErrNotFound
+}
+
+// Host returns the host name of the JIRA base url, which is used to look up credentials
+func Host(baseURL string) (string, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	if parsedURL.Hostname() == "" {
+		return "", errors.New("Base url must contain a host")
+	}
+
+	return parsedURL.Hostname(), nil
+}
+
+// empty reports if the credentials do not contain a secret
+func (c *Credentials) empty() bool {
+	return c.Password == "" && c.Token == ""
+}
@@ -0,0 +1,55 @@
+package credentials_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fixedProvider struct {
+	credentials *credentials.Credentials
+	err         error
+}
+
+func (p fixedProvider) Credentials(host string) (*credentials.Credentials, error) {
+	return p.credentials, p.err
+}
+
+func TestChainReturnsFirstFound(t *testing.T) {
+	expected := &credentials.Credentials{Username: "me", Password: "secret"}
+	chain := credentials.Chain{
+		fixedProvider{err: credentials.ErrNotFound},
+		fixedProvider{credentials: expected},
+		fixedProvider{credentials: &credentials.Credentials{Token: "unused"}},
+	}
+
+	result, err := chain.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestChainReturnsError(t *testing.T) {
+	chain := credentials.Chain{fixedProvider{err: errors.New("Locked")}}
+	result, err := chain.Credentials("jira.example.com")
+
+	assert.Equal(t, errors.New("Locked"), err)
+	assert.Nil(t, result)
+}
+
+func TestChainNotFound(t *testing.T) {
+	result, err := credentials.Chain{}.Credentials("jira.example.com")
+
+	assert.Equal(t, credentials.ErrNotFound, err)
+	assert.Nil(t, result)
+}
+
+func TestHost(t *testing.T) {
+	host, err := credentials.Host("https://jira.example.com:8443/jira/")
+	assert.Nil(t, err)
+	assert.Equal(t, "jira.example.com", host)
+
+	_, err = credentials.Host("jira")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,32 @@
+package credentials
+
+import (
+	"os"
+)
+
+// Env is a Provider that reads the credentials from env variables, e.g. JIRA_USERNAME, JIRA_PASSWORD and JIRA_TOKEN.
+// The same credentials are returned for every host
+type Env struct {
+	// Prefix of the env variables, defaults to JIRA
+	Prefix string
+}
+
+// Credentials returns the credentials from the env variables, ErrNotFound is returned when no secret is set
+func (e Env) Credentials(host string) (*Credentials, error) {
+	prefix := e.Prefix
+	if prefix == "" {
+		prefix = "JIRA"
+	}
+
+	credentials := Credentials{
+		Username: os.Getenv(prefix + "_USERNAME"),
+		Password: os.Getenv(prefix + "_PASSWORD"),
+		Token:    os.Getenv(prefix + "_TOKEN"),
+	}
+
+	if credentials.empty() {
+		return nil, ErrNotFound
+	}
+
+	return &credentials, nil
+}
@@ -0,0 +1,27 @@
+package credentials_test
+
+import (
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	os.Setenv("TEST_JIRA_USERNAME", "me")
+	os.Setenv("TEST_JIRA_PASSWORD", "secret")
+	defer os.Unsetenv("TEST_JIRA_USERNAME")
+	defer os.Unsetenv("TEST_JIRA_PASSWORD")
+
+	result, err := credentials.Env{Prefix: "TEST_JIRA"}.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Username: "me", Password: "secret"}, result)
+}
+
+func TestEnvCredentialsNotFound(t *testing.T) {
+	result, err := credentials.Env{Prefix: "MISSING_JIRA"}.Credentials("jira.example.com")
+
+	assert.Equal(t, credentials.ErrNotFound, err)
+	assert.Nil(t, result)
+}
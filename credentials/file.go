@@ -0,0 +1,138 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"filippo.io/age"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// EncryptedFile is a Provider and Store that keeps the credentials of all hosts in a file that is encrypted with a
+// passphrase using age (https://age-encryption.org). The file can also be decrypted with the age command line tool
+type EncryptedFile struct {
+	Path string
+	// Passphrase returns the passphrase used to encrypt and decrypt the file
+	Passphrase func() (string, error)
+}
+
+// Credentials decrypts the file and returns the credentials for the host
+func (f EncryptedFile) Credentials(host string) (*Credentials, error) {
+	content, err := f.content()
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := f.decrypt(content, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, ok := all[host]
+	if !ok || credentials.empty() {
+		return nil, ErrNotFound
+	}
+
+	return &credentials, nil
+}
+
+// Store adds or replaces the credentials for the host and encrypts the file again. The passphrase is asked for once,
+// it decrypts the existing file and encrypts the new one
+func (f EncryptedFile) Store(host string, credentials Credentials) error {
+	content, err := f.content()
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return err
+	}
+
+	all := make(map[string]Credentials)
+	if content != nil {
+		if all, err = f.decrypt(content, passphrase); err != nil {
+			return err
+		}
+	}
+
+	all[host] = credentials
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return err
+	}
+
+	buffer := new(bytes.Buffer)
+	writer, err := age.Encrypt(buffer, recipient)
+	if err != nil {
+		return err
+	}
+
+	if err = json.NewEncoder(writer).Encode(all); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(f.Path, buffer.Bytes(), 0600)
+}
+
+// content returns the encrypted content of the file, ErrNotFound is returned when the file does not exist
+func (f EncryptedFile) content() ([]byte, error) {
+	content, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return content, err
+}
+
+// decrypt returns the credentials of all hosts in the encrypted content
+func (f EncryptedFile) decrypt(content []byte, passphrase string) (map[string]Credentials, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := age.Decrypt(bytes.NewReader(content), identity)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decrypt %v: %v", f.Path, err)
+	}
+
+	var all map[string]Credentials
+	if err = json.NewDecoder(reader).Decode(&all); err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+func (f EncryptedFile) passphrase() (string, error) {
+	if f.Passphrase == nil {
+		return "", fmt.Errorf("Passphrase cannot be empty")
+	}
+
+	passphrase, err := f.Passphrase()
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase == "" {
+		return "", fmt.Errorf("Passphrase cannot be empty")
+	}
+
+	return passphrase, nil
+}
@@ -0,0 +1,70 @@
+package credentials_test
+
+import (
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func passphrase(value string) func() (string, error) {
+	return func() (string, error) {
+		return value, nil
+	}
+}
+
+func TestEncryptedFileStoreAndRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nested", "credentials.age")
+	file := credentials.EncryptedFile{Path: path, Passphrase: passphrase("correct horse")}
+
+	_, err = file.Credentials("jira.example.com")
+	assert.Equal(t, credentials.ErrNotFound, err)
+
+	err = file.Store("jira.example.com", credentials.Credentials{Token: "token"})
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "age-encryption.org/v1"))
+	assert.NotContains(t, string(content), "token")
+
+	result, err := file.Credentials("jira.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Token: "token"}, result)
+
+	_, err = file.Credentials("other.example.com")
+	assert.Equal(t, credentials.ErrNotFound, err)
+
+	wrong := credentials.EncryptedFile{Path: path, Passphrase: passphrase("wrong")}
+	_, err = wrong.Credentials("jira.example.com")
+	assert.NotNil(t, err)
+}
+
+func TestEncryptedFileStoreAsksPassphraseOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	asked := 0
+	file := credentials.EncryptedFile{Path: filepath.Join(dir, "credentials.age"), Passphrase: func() (string, error) {
+		asked++
+		return "correct horse", nil
+	}}
+
+	assert.Nil(t, file.Store("jira.example.com", credentials.Credentials{Token: "token"}))
+	assert.Equal(t, 1, asked)
+
+	assert.Nil(t, file.Store("other.example.com", credentials.Credentials{Username: "me", Password: "secret"}))
+	assert.Equal(t, 2, asked)
+
+	result, err := file.Credentials("jira.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Token: "token"}, result)
+}
@@ -0,0 +1,83 @@
+package credentials
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Netrc is a Provider that reads the login and password for the host from a netrc file
+type Netrc struct {
+	// Path of the netrc file, defaults to ~/.netrc
+	Path string
+}
+
+// Credentials returns the login and password of the machine entry for the host, or of the default entry
+func (n Netrc) Credentials(host string) (*Credentials, error) {
+	path := n.Path
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var machine, fallback *Credentials
+	var current *Credentials
+	tokens := strings.Fields(string(content))
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			current = nil
+			if i+1 < len(tokens) {
+				i++
+				if tokens[i] == host && machine == nil {
+					machine = &Credentials{}
+					current = machine
+				}
+			}
+		case "default":
+			current = nil
+			if fallback == nil {
+				fallback = &Credentials{}
+				current = fallback
+			}
+		case "login", "password", "account":
+			if i+1 >= len(tokens) {
+				break
+			}
+			i++
+			if current == nil {
+				continue
+			}
+			if tokens[i-1] == "login" {
+				current.Username = tokens[i]
+			} else if tokens[i-1] == "password" {
+				current.Password = tokens[i]
+			}
+		case "macdef":
+			// Macro definitions run until an empty line and are not supported, stop parsing
+			i = len(tokens)
+		}
+	}
+
+	if machine != nil && !machine.empty() {
+		return machine, nil
+	}
+	if fallback != nil && !fallback.empty() {
+		return fallback, nil
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,54 @@
+package credentials_test
+
+import (
+	"github.com/marcelblijleven/version-meister/credentials"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testNetrc = `machine github.com login octocat password not-this-one
+machine jira.example.com
+  login me@example.com
+  password api-token
+default login anonymous password fallback
+`
+
+func writeNetrc(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "netrc")
+	assert.Nil(t, err)
+
+	path := filepath.Join(dir, ".netrc")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testNetrc), 0600))
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestNetrcCredentials(t *testing.T) {
+	path, cleanup := writeNetrc(t)
+	defer cleanup()
+
+	result, err := credentials.Netrc{Path: path}.Credentials("jira.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Username: "me@example.com", Password: "api-token"}, result)
+}
+
+func TestNetrcCredentialsDefault(t *testing.T) {
+	path, cleanup := writeNetrc(t)
+	defer cleanup()
+
+	result, err := credentials.Netrc{Path: path}.Credentials("other.example.com")
+
+	assert.Nil(t, err)
+	assert.Equal(t, &credentials.Credentials{Username: "anonymous", Password: "fallback"}, result)
+}
+
+func TestNetrcMissingFile(t *testing.T) {
+	result, err := credentials.Netrc{Path: "/does/not/exist/.netrc"}.Credentials("jira.example.com")
+
+	assert.Equal(t, credentials.ErrNotFound, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,312 @@
+package fakejira
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPageSize is the number of search results and comments per page when SetPageSize is not used
+const DefaultPageSize = 50
+
+// Server is an in-memory JIRA that serves the REST API endpoints used by api.Client. It keeps state, so versions,
+// fixVersions, comments and transitions made through the client are visible to later requests and to the test
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	projects    []jira.Project
+	versions    []jira.Version
+	issues      []*issueState
+	transitions []jira.Transition
+	myself      jira.User
+	pageSize    int
+	latency     time.Duration
+	failures    []failure
+	requests    []string
+	username    string
+	password    string
+	token       string
+	nextID      int
+}
+
+type issueState struct {
+	issue     jira.Issue
+	comments  []jira.Comment
+	changelog []jira.ChangelogEntry
+}
+
+type failure struct {
+	method string
+	path   string
+	status int
+}
+
+// New starts a fake JIRA server, close it with Close when the test is done
+func New() *Server {
+	s := &Server{
+		myself:   jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5", Name: "username", DisplayName: "Fake User"},
+		pageSize: DefaultPageSize,
+		nextID:   10000,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an api client that uses basic authentication with the credentials required by RequireAuth,
+// or placeholder credentials when no authentication is required
+func (s *Server) Client() *api.Client {
+	s.mu.Lock()
+	username, password := s.username, s.password
+	s.mu.Unlock()
+
+	if username == "" {
+		username, password = "username", "password"
+	}
+
+	client, _ := api.NewClient(s.URL, username, password)
+	return client
+}
+
+// RequireAuth makes the server respond 401 to requests without these basic auth credentials
+func (s *Server) RequireAuth(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+}
+
+// RequireToken makes the server respond 401 to requests without this bearer token
+func (s *Server) RequireToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// SetPageSize sets the maximum number of search results and comments per page
+func (s *Server) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = size
+}
+
+// SetLatency delays every response by the duration
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// FailNext makes the next request with the method and path respond with the status code. The path is relative to
+// rest/api/<version>/, e.g. "issue/AB-1", and an empty method matches every method. Call it again to fail more requests
+func (s *Server) FailNext(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, path: strings.Trim(path, "/"), status: status})
+}
+
+// SetMyself sets the user that the myself endpoint returns
+func (s *Server) SetMyself(user jira.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.myself = user
+}
+
+// SetTransitions sets the workflow transitions, every transition is available to issues that are not in its status
+func (s *Server) SetTransitions(transitions ...jira.Transition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transitions = transitions
+}
+
+// AddProject adds a project, issues and versions refer to it by ID
+func (s *Server) AddProject(id int, key string) jira.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project := jira.Project{ID: strconv.Itoa(id), Key: key, Self: s.url("project/%d", id)}
+	s.projects = append(s.projects, project)
+	return project
+}
+
+// AddVersion adds a version and returns it with its ID set
+func (s *Server) AddVersion(version jira.Version) jira.Version {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addVersion(version)
+}
+
+// AddIssue adds an issue and returns it with its ID set. The key defaults to the project key and the ID
+func (s *Server) AddIssue(issue jira.Issue) jira.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if issue.ID == "" {
+		issue.ID = s.newID()
+	}
+	if issue.Fields == nil {
+		issue.Fields = &jira.IssueFields{}
+	}
+	if issue.Fields.Project.ID != "" && issue.Fields.Project.Key == "" {
+		for _, project := range s.projects {
+			if project.ID == issue.Fields.Project.ID {
+				issue.Fields.Project = project
+			}
+		}
+	}
+	if issue.Key == "" {
+		issue.Key = fmt.Sprintf("%v-%v", issue.Fields.Project.Key, issue.ID)
+	}
+	issue.Self = s.url("issue/%v", issue.ID)
+
+	s.issues = append(s.issues, &issueState{issue: issue})
+	return issue
+}
+
+// AddComment adds a comment to the issue with the key or ID
+func (s *Server) AddComment(issue string, comment jira.Comment) (jira.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.findIssue(issue)
+	if state == nil {
+		return comment, fmt.Errorf("Issue %v does not exist", issue)
+	}
+
+	comment.ID = s.newID()
+	state.comments = append(state.comments, comment)
+	return comment, nil
+}
+
+// AddChangelog adds a changelog entry to the issue with the key or ID, e.g. to simulate a fixVersion that was set
+// by hand. The ID and the created time are set when they are empty. The issue itself is not changed
+func (s *Server) AddChangelog(issue string, entry jira.ChangelogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.findIssue(issue)
+	if state == nil {
+		return fmt.Errorf("Issue %v does not exist", issue)
+	}
+
+	if entry.ID == "" {
+		entry.ID = s.newID()
+	}
+	if entry.Created == "" {
+		entry.Created = time.Now().Format(jira.ChangelogTimeLayout)
+	}
+	state.changelog = append(state.changelog, entry)
+	return nil
+}
+
+// Changelog returns the changelog of the issue with the key or ID. Changes to fixVersions and transitions made
+// through the API are recorded with the user of SetMyself as author
+func (s *Server) Changelog(issue string) []jira.ChangelogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.findIssue(issue)
+	if state == nil {
+		return nil
+	}
+	return append([]jira.ChangelogEntry(nil), state.changelog...)
+}
+
+// Issue returns the current state of the issue with the key or ID
+func (s *Server) Issue(issue string) (jira.Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.findIssue(issue)
+	if state == nil {
+		return jira.Issue{}, false
+	}
+	return state.issue, true
+}
+
+// Comments returns the comments on the issue with the key or ID
+func (s *Server) Comments(issue string) []jira.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.findIssue(issue)
+	if state == nil {
+		return nil
+	}
+	return append([]jira.Comment(nil), state.comments...)
+}
+
+// Versions returns the versions of the project
+func (s *Server) Versions(projectID int) []jira.Version {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var versions []jira.Version
+	for _, version := range s.versions {
+		if version.ProjectID == projectID {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// Requests returns the requests the server received as "METHOD path", the path is relative to rest/api/<version>/
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) addVersion(version jira.Version) jira.Version {
+	version.ID = s.newID()
+	version.Self = s.url("version/%v", version.ID)
+	s.versions = append(s.versions, version)
+	return version
+}
+
+func (s *Server) findIssue(idOrKey string) *issueState {
+	for _, state := range s.issues {
+		if state.issue.ID == idOrKey || strings.EqualFold(state.issue.Key, idOrKey) {
+			return state
+		}
+	}
+	return nil
+}
+
+func (s *Server) findVersion(id string) *jira.Version {
+	for i := range s.versions {
+		if s.versions[i].ID == id {
+			return &s.versions[i]
+		}
+	}
+	return nil
+}
+
+// recordChange adds a changelog entry with the items to the issue, authored by the current user
+func (s *Server) recordChange(state *issueState, items ...jira.ChangelogItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	author := s.myself
+	state.changelog = append(state.changelog, jira.ChangelogEntry{
+		ID:      s.newID(),
+		Author:  &author,
+		Created: time.Now().Format(jira.ChangelogTimeLayout),
+		Items:   items,
+	})
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) url(format string, args ...interface{}) string {
+	return s.URL + "/rest/api/latest/" + fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,270 @@
+package fakejira_test
+
+import (
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testServer() *fakejira.Server {
+	server := fakejira.New()
+	server.AddProject(1337, "AB")
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Status:  &jira.Status{Name: "Ready for Release"},
+	}})
+	return server
+}
+
+func testClient(server *fakejira.Server) *api.Client {
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	return client
+}
+
+func TestAddIssueSetsDefaults(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+	server.AddProject(1337, "AB")
+
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+
+	assert.NotEmpty(t, issue.ID)
+	assert.Equal(t, "AB-"+issue.ID, issue.Key)
+	assert.Equal(t, "AB", issue.Fields.Project.Key)
+	assert.Equal(t, server.URL+"/rest/api/latest/issue/"+issue.ID, issue.Self)
+}
+
+func TestGetIssue(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	issue, err := testClient(server).GetIssue("AB-1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "AB-1", issue.Key)
+	assert.Equal(t, "Ready for Release", issue.Fields.Status.Name)
+
+	_, err = testClient(server).GetIssue("AB-2")
+
+	assert.Equal(t, api.ErrIssueNotFound, err)
+}
+
+func TestCreateVersionAndAddToIssue(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	issue, _ := server.Issue("AB-1")
+
+	assert.Nil(t, client.CreateVersion(jira.Version{Name: "1.0.0", ProjectID: 1337}))
+	// Creating the same version again uses the existing version
+	assert.Nil(t, client.CreateVersion(jira.Version{Name: "1.0.0", ProjectID: 1337}))
+	assert.Len(t, server.Versions(1337), 1)
+
+	assert.Nil(t, client.AddVersionToIssue(issue, jira.Version{Name: "1.0.0"}))
+	issue, _ = server.Issue("AB-1")
+	assert.Len(t, issue.Fields.FixVersions, 1)
+	assert.Equal(t, "1.0.0", issue.Fields.FixVersions[0].Name)
+
+	assert.Nil(t, client.RemoveVersionFromIssue(issue, jira.Version{Name: "1.0.0"}))
+	issue, _ = server.Issue("AB-1")
+	assert.Empty(t, issue.Fields.FixVersions)
+}
+
+func TestAddUnknownVersionToIssueReturnsError(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	issue, _ := server.Issue("AB-1")
+
+	err := testClient(server).AddVersionToIssue(issue, jira.Version{Name: "9.9.9"})
+
+	assert.Equal(t, "AddVersion response status is 400", err.Error())
+}
+
+func TestReleaseAndDeleteVersion(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	server.AddVersion(jira.Version{Name: "1.0.0", ProjectID: 1337})
+
+	version, err := client.FindVersion(1337, "1.0.0")
+	assert.Nil(t, err)
+	assert.False(t, version.Released)
+
+	version.ReleaseDate = "2020-06-01"
+	assert.Nil(t, client.ReleaseVersion(*version))
+	assert.True(t, server.Versions(1337)[0].Released)
+	assert.Equal(t, "2020-06-01", server.Versions(1337)[0].ReleaseDate)
+
+	assert.Nil(t, client.DeleteVersion(*version))
+	assert.Empty(t, server.Versions(1337))
+}
+
+func TestComments(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	issue, _ := server.Issue("AB-1")
+
+	created, err := client.CreateComment(issue, jira.Comment{Body: "Released in 1.0.0"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "Fake User", created.Author.DisplayName)
+
+	created.Body = "Released in 1.0.1"
+	assert.Nil(t, client.UpdateComment(issue, *created))
+
+	comments, err := client.ListComments(issue)
+	assert.Nil(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Released in 1.0.1", comments[0].Body)
+
+	assert.Nil(t, client.DeleteComment(issue, created.ID))
+	assert.Empty(t, server.Comments("AB-1"))
+}
+
+func TestTransitions(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	server.SetTransitions(
+		jira.Transition{ID: "11", Name: "Release", To: &jira.Status{Name: "Released"}},
+		jira.Transition{ID: "21", Name: "Reopen", To: &jira.Status{Name: "Ready for Release"}},
+	)
+	issue, _ := server.Issue("AB-1")
+
+	transitions, err := client.GetTransitions(issue)
+	assert.Nil(t, err)
+	assert.Len(t, transitions, 1)
+	assert.Equal(t, "Release", transitions[0].Name)
+
+	assert.Nil(t, client.TransitionIssue(issue, "11"))
+	issue, _ = server.Issue("AB-1")
+	assert.Equal(t, "Released", issue.Fields.Status.Name)
+
+	assert.NotNil(t, client.TransitionIssue(issue, "11"))
+}
+
+func TestSearchPagination(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	server.SetPageSize(2)
+	for i := 0; i < 4; i++ {
+		server.AddIssue(jira.Issue{Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+	}
+
+	issues, err := testClient(server).Search("project = AB")
+
+	assert.Nil(t, err)
+	assert.Len(t, issues, 5)
+	assert.Equal(t, []string{
+		"GET search",
+		"GET search",
+		"GET search",
+	}, server.Requests())
+}
+
+func TestRequireAuth(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	server.RequireAuth("user@example.com", "secret")
+
+	_, err := testClient(server).Myself()
+	assert.Nil(t, err)
+
+	client, _ := api.NewClient(server.URL, "user@example.com", "wrong")
+	_, err = client.Myself()
+	assert.True(t, api.IsAuthError(err))
+}
+
+func TestRequireToken(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	server.RequireToken("token")
+
+	client, _ := api.NewTokenClient(server.URL, "token")
+	user, err := client.Myself()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "username", user.Name)
+
+	_, err = testClient(server).Myself()
+	assert.True(t, api.IsAuthError(err))
+}
+
+func TestFailNext(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	server.FailNext("GET", "issue/AB-1", http.StatusInternalServerError)
+
+	_, err := client.GetIssue("AB-1")
+	assert.Equal(t, "GetIssue response status is 500", err.Error())
+
+	_, err = client.GetIssue("AB-1")
+	assert.Nil(t, err)
+}
+
+func TestSetLatency(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	server.SetLatency(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := testClient(server).GetIssue("AB-1")
+
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestAPIVersion3Comment(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	client.SetAPIVersion(api.APIVersion3)
+	issue, _ := server.Issue("AB-1")
+
+	_, err := client.CreateComment(issue, jira.Comment{Body: "Released"})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, server.Comments("AB-1")[0].Document)
+	assert.Equal(t, []string{"POST issue/" + issue.ID + "/comment"}, server.Requests())
+}
+
+func TestChangelog(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+	client := testClient(server)
+	server.SetPageSize(1)
+	server.SetMyself(jira.User{Name: "release-bot"})
+	server.SetTransitions(jira.Transition{ID: "11", Name: "Release", To: &jira.Status{ID: "10000", Name: "Released"}})
+	version := server.AddVersion(jira.Version{Name: "1.2.0", ProjectID: 1337})
+	issue, _ := server.Issue("AB-1")
+
+	assert.Nil(t, client.AddVersionToIssue(issue, version))
+	assert.Nil(t, client.TransitionIssue(issue, "11"))
+	assert.Nil(t, server.AddChangelog("AB-1", jira.ChangelogEntry{Author: &jira.User{Name: "jdoe"}, Items: []jira.ChangelogItem{{Field: "labels", ToString: "backend"}}}))
+
+	changelog, err := client.GetIssueChangelog(issue)
+
+	assert.Nil(t, err)
+	assert.Equal(t, server.Changelog("AB-1"), changelog)
+	assert.Len(t, changelog, 3)
+	assert.Equal(t, "release-bot", changelog[0].Author.Name)
+	assert.Equal(t, jira.ChangelogItem{Field: "Fix Version", FieldType: "jira", FieldID: "fixVersions", To: version.ID, ToString: "1.2.0"}, changelog[0].Items[0])
+	assert.Equal(t, "Ready for Release", changelog[1].Items[0].FromString)
+	assert.Equal(t, "Released", changelog[1].Items[0].ToString)
+	assert.Equal(t, "jdoe", changelog[2].Author.Name)
+	assert.Equal(t, []string{
+		"PUT issue/" + issue.ID,
+		"POST issue/" + issue.ID + "/transitions",
+		"GET issue/" + issue.ID + "/changelog",
+		"GET issue/" + issue.ID + "/changelog",
+		"GET issue/" + issue.ID + "/changelog",
+	}, server.Requests())
+}
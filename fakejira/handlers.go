@@ -0,0 +1,563 @@
+package fakejira
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var apiPath = regexp.MustCompile(`^/rest/api/(2|3|latest)/(.+)$`)
+
+// versionExists is the error message JIRA returns when a version name is taken, api.Client relies on it
+const versionExists = "A version with this name already exists in this project."
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	matches := apiPath.FindStringSubmatch(r.URL.Path)
+	if matches == nil {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("No endpoint %v", r.URL.Path))
+		return
+	}
+	path := strings.Trim(matches[2], "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	if s.latency > 0 {
+		// Release the lock while waiting, so concurrent requests are delayed in parallel
+		latency := s.latency
+		s.mu.Unlock()
+		time.Sleep(latency)
+		s.mu.Lock()
+	}
+
+	if !s.authorized(r) {
+		writeErrors(w, http.StatusUnauthorized, "You are not authenticated")
+		return
+	}
+
+	if status, ok := s.injectedFailure(r.Method, path); ok {
+		writeErrors(w, status, fmt.Sprintf("Injected failure for %v %v", r.Method, path))
+		return
+	}
+
+	segments := strings.Split(path, "/")
+	switch {
+	case path == "search" && r.Method == http.MethodGet:
+		s.search(w, r)
+	case path == "myself" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.myself)
+	case path == "version" && r.Method == http.MethodPost:
+		s.createVersion(w, r)
+	case len(segments) == 2 && segments[0] == "project" && r.Method == http.MethodGet:
+		s.project(w, segments[1])
+	case len(segments) == 3 && segments[0] == "project" && segments[2] == "versions" && r.Method == http.MethodGet:
+		s.projectVersions(w, segments[1])
+	case len(segments) == 2 && segments[0] == "version":
+		s.version(w, r, segments[1])
+	case len(segments) == 2 && segments[0] == "issue":
+		s.issue(w, r, segments[1])
+	case len(segments) >= 3 && segments[0] == "issue" && segments[2] == "comment":
+		s.comment(w, r, segments[1], segments[3:])
+	case len(segments) == 3 && segments[0] == "issue" && segments[2] == "transitions":
+		s.transition(w, r, segments[1])
+	case len(segments) == 3 && segments[0] == "issue" && segments[2] == "changelog" && r.Method == http.MethodGet:
+		s.changelog(w, r, segments[1])
+	default:
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("No endpoint %v %v", r.Method, path))
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token != "" {
+		return r.Header.Get("Authorization") == "Bearer "+s.token
+	}
+	if s.username != "" {
+		username, password, ok := r.BasicAuth()
+		return ok && username == s.username && password == s.password
+	}
+	return true
+}
+
+func (s *Server) injectedFailure(method, path string) (int, bool) {
+	for i, f := range s.failures {
+		if (f.method == "" || strings.EqualFold(f.method, method)) && f.path == path {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			return f.status, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	match, err := parseJQL(r.URL.Query().Get("jql"), func(key string) bool { return s.findIssue(key) != nil })
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var issues []jira.Issue
+	for _, state := range s.issues {
+		if match(state.issue) {
+			issues = append(issues, state.issue)
+		}
+	}
+
+	startAt, maxResults := s.page(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issues),
+		"issues":     pageOf(issues, startAt, maxResults),
+	})
+}
+
+func (s *Server) createVersion(w http.ResponseWriter, r *http.Request) {
+	var version jira.Version
+	if err := json.NewDecoder(r.Body).Decode(&version); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if version.Name == "" {
+		writeFieldError(w, "name", "You must specify a valid version name")
+		return
+	}
+
+	for _, existing := range s.versions {
+		if existing.ProjectID == version.ProjectID && existing.Name == version.Name {
+			writeFieldError(w, "name", versionExists)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, s.addVersion(version))
+}
+
+func (s *Server) project(w http.ResponseWriter, idOrKey string) {
+	for _, project := range s.projects {
+		if project.ID == idOrKey || strings.EqualFold(project.Key, idOrKey) {
+			writeJSON(w, http.StatusOK, project)
+			return
+		}
+	}
+
+	writeErrors(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%v'.", idOrKey))
+}
+
+func (s *Server) projectVersions(w http.ResponseWriter, idOrKey string) {
+	projectID, ok := s.projectID(idOrKey)
+	if !ok {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%v'.", idOrKey))
+		return
+	}
+
+	versions := []jira.Version{}
+	for _, version := range s.versions {
+		if version.ProjectID == projectID {
+			versions = append(versions, version)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request, id string) {
+	version := s.findVersion(id)
+	if version == nil {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%v'", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, version)
+	case http.MethodPut:
+		var update struct {
+			Name        *string `json:"name"`
+			Description *string `json:"description"`
+			Archived    *bool   `json:"archived"`
+			Released    *bool   `json:"released"`
+			ReleaseDate *string `json:"releaseDate"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if update.Name != nil {
+			version.Name = *update.Name
+		}
+		if update.Description != nil {
+			version.Description = *update.Description
+		}
+		if update.Archived != nil {
+			version.Archived = *update.Archived
+		}
+		if update.Released != nil {
+			version.Released = *update.Released
+		}
+		if update.ReleaseDate != nil {
+			version.ReleaseDate = *update.ReleaseDate
+		}
+		writeJSON(w, http.StatusOK, version)
+	case http.MethodDelete:
+		for i := range s.versions {
+			if s.versions[i].ID == id {
+				s.versions = append(s.versions[:i], s.versions[i+1:]...)
+				break
+			}
+		}
+		for _, state := range s.issues {
+			state.issue.Fields.FixVersions = removeFixVersion(state.issue.Fields.FixVersions, jira.Version{ID: id})
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) issue(w http.ResponseWriter, r *http.Request, idOrKey string) {
+	state := s.findIssue(idOrKey)
+	if state == nil {
+		writeErrors(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !strings.Contains(r.URL.Query().Get("expand"), "changelog") {
+			writeJSON(w, http.StatusOK, state.issue)
+			return
+		}
+		// JIRA Server and Data Center return the full changelog with the issue
+		writeJSON(w, http.StatusOK, struct {
+			jira.Issue
+			Changelog map[string]interface{} `json:"changelog"`
+		}{state.issue, map[string]interface{}{
+			"startAt":    0,
+			"maxResults": len(state.changelog),
+			"total":      len(state.changelog),
+			"histories":  append([]jira.ChangelogEntry{}, state.changelog...),
+		}})
+	case http.MethodPut:
+		var update struct {
+			Update struct {
+				FixVersions []struct {
+					Add    *jira.Version  `json:"add"`
+					Remove *jira.Version  `json:"remove"`
+					Set    []jira.Version `json:"set"`
+				} `json:"fixVersions"`
+			} `json:"update"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		fixVersions := state.issue.Fields.FixVersions
+		for _, operation := range update.Update.FixVersions {
+			var err error
+			switch {
+			case operation.Add != nil:
+				fixVersions, err = s.addFixVersion(state.issue, fixVersions, *operation.Add)
+			case operation.Remove != nil:
+				fixVersions = removeFixVersion(fixVersions, *operation.Remove)
+			case operation.Set != nil:
+				fixVersions = nil
+				for _, version := range operation.Set {
+					if fixVersions, err = s.addFixVersion(state.issue, fixVersions, version); err != nil {
+						break
+					}
+				}
+			}
+			if err != nil {
+				writeFieldError(w, "fixVersions", err.Error())
+				return
+			}
+		}
+
+		s.recordChange(state, fixVersionItems(state.issue.Fields.FixVersions, fixVersions)...)
+		state.issue.Fields.FixVersions = fixVersions
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// addFixVersion adds the project version with the name or ID of the reference to the fixVersions
+func (s *Server) addFixVersion(issue jira.Issue, fixVersions []jira.Version, reference jira.Version) ([]jira.Version, error) {
+	projectID, _ := strconv.Atoi(issue.Fields.Project.ID)
+
+	for _, version := range s.versions {
+		if version.ProjectID != projectID {
+			continue
+		}
+		if (reference.ID == "" || version.ID != reference.ID) && (reference.Name == "" || version.Name != reference.Name) {
+			continue
+		}
+		for _, existing := range fixVersions {
+			if existing.ID == version.ID {
+				return fixVersions, nil
+			}
+		}
+		return append(fixVersions, version), nil
+	}
+
+	return nil, fmt.Errorf("Version name '%v' is not valid", reference.Name)
+}
+
+func (s *Server) comment(w http.ResponseWriter, r *http.Request, idOrKey string, rest []string) {
+	state := s.findIssue(idOrKey)
+	if state == nil {
+		writeErrors(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			startAt, maxResults := s.page(r)
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"startAt":    startAt,
+				"maxResults": maxResults,
+				"total":      len(state.comments),
+				"comments":   pageOf(state.comments, startAt, maxResults),
+			})
+		case http.MethodPost:
+			var comment jira.Comment
+			if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if comment.Body == "" && comment.Document == nil {
+				writeFieldError(w, "comment", "Comment body can not be empty!")
+				return
+			}
+			comment.ID = s.newID()
+			comment.Author = &s.myself
+			state.comments = append(state.comments, comment)
+			writeJSON(w, http.StatusCreated, comment)
+		default:
+			writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	index := -1
+	for i, comment := range state.comments {
+		if len(rest) == 1 && comment.ID == rest[0] {
+			index = i
+		}
+	}
+	if index == -1 {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("Can not find a comment for the id: %v.", strings.Join(rest, "/")))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, state.comments[index])
+	case http.MethodPut:
+		var comment jira.Comment
+		if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		comment.ID = state.comments[index].ID
+		comment.Author = state.comments[index].Author
+		state.comments[index] = comment
+		writeJSON(w, http.StatusOK, comment)
+	case http.MethodDelete:
+		state.comments = append(state.comments[:index], state.comments[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) transition(w http.ResponseWriter, r *http.Request, idOrKey string) {
+	state := s.findIssue(idOrKey)
+	if state == nil {
+		writeErrors(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": s.availableTransitions(state.issue)})
+	case http.MethodPost:
+		var request struct {
+			Transition jira.Transition `json:"transition"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, transition := range s.availableTransitions(state.issue) {
+			if transition.ID == request.Transition.ID {
+				status := *transition.To
+				item := jira.ChangelogItem{Field: "status", FieldType: "jira", FieldID: "status", To: status.ID, ToString: status.Name}
+				if state.issue.Fields.Status != nil {
+					item.From, item.FromString = state.issue.Fields.Status.ID, state.issue.Fields.Status.Name
+				}
+				s.recordChange(state, item)
+				state.issue.Fields.Status = &status
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeErrors(w, http.StatusBadRequest,
+			fmt.Sprintf("Transition id '%v' is not valid for this issue.", request.Transition.ID))
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) changelog(w http.ResponseWriter, r *http.Request, idOrKey string) {
+	state := s.findIssue(idOrKey)
+	if state == nil {
+		writeErrors(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	startAt, maxResults := s.page(r)
+	values := pageOf(state.changelog, startAt, maxResults).([]jira.ChangelogEntry)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(state.changelog),
+		"isLast":     startAt+len(values) >= len(state.changelog),
+		"values":     values,
+	})
+}
+
+// fixVersionItems returns the changelog items for the versions that were added and removed
+func fixVersionItems(before, after []jira.Version) []jira.ChangelogItem {
+	var items []jira.ChangelogItem
+	for _, version := range before {
+		if !containsVersion(after, version.ID) {
+			items = append(items, jira.ChangelogItem{Field: "Fix Version", FieldType: "jira", FieldID: jira.FixVersionField, From: version.ID, FromString: version.Name})
+		}
+	}
+	for _, version := range after {
+		if !containsVersion(before, version.ID) {
+			items = append(items, jira.ChangelogItem{Field: "Fix Version", FieldType: "jira", FieldID: jira.FixVersionField, To: version.ID, ToString: version.Name})
+		}
+	}
+	return items
+}
+
+func containsVersion(versions []jira.Version, id string) bool {
+	for _, version := range versions {
+		if version.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// availableTransitions returns the transitions to a status other than the current status of the issue
+func (s *Server) availableTransitions(issue jira.Issue) []jira.Transition {
+	transitions := []jira.Transition{}
+	for _, transition := range s.transitions {
+		if transition.To == nil {
+			continue
+		}
+		if issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, transition.To.Name) {
+			continue
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions
+}
+
+// projectID returns the numeric ID of the project with the ID or key
+func (s *Server) projectID(idOrKey string) (int, bool) {
+	for _, project := range s.projects {
+		if project.ID == idOrKey || strings.EqualFold(project.Key, idOrKey) {
+			id, err := strconv.Atoi(project.ID)
+			return id, err == nil
+		}
+	}
+
+	// Versions can be added for projects that were not added explicitly
+	id, err := strconv.Atoi(idOrKey)
+	return id, err == nil
+}
+
+// page returns the startAt and maxResults query parameters, maxResults is capped at the page size
+func (s *Server) page(r *http.Request) (int, int) {
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	maxResults, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if err != nil || maxResults <= 0 || maxResults > s.pageSize {
+		maxResults = s.pageSize
+	}
+	if startAt < 0 {
+		startAt = 0
+	}
+	return startAt, maxResults
+}
+
+func pageOf(items interface{}, startAt, maxResults int) interface{} {
+	switch list := items.(type) {
+	case []jira.Issue:
+		if startAt > len(list) {
+			startAt = len(list)
+		}
+		end := startAt + maxResults
+		if end > len(list) {
+			end = len(list)
+		}
+		return append([]jira.Issue{}, list[startAt:end]...)
+	case []jira.Comment:
+		if startAt > len(list) {
+			startAt = len(list)
+		}
+		end := startAt + maxResults
+		if end > len(list) {
+			end = len(list)
+		}
+		return append([]jira.Comment{}, list[startAt:end]...)
+	case []jira.ChangelogEntry:
+		if startAt > len(list) {
+			startAt = len(list)
+		}
+		end := startAt + maxResults
+		if end > len(list) {
+			end = len(list)
+		}
+		return append([]jira.ChangelogEntry{}, list[startAt:end]...)
+	}
+	return items
+}
+
+func removeFixVersion(fixVersions []jira.Version, reference jira.Version) []jira.Version {
+	var result []jira.Version
+	for _, version := range fixVersions {
+		if (reference.ID != "" && version.ID == reference.ID) || (reference.Name != "" && version.Name == reference.Name) {
+			continue
+		}
+		result = append(result, version)
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	writeJSON(w, status, map[string]interface{}{"errorMessages": messages, "errors": map[string]string{}})
+}
+
+func writeFieldError(w http.ResponseWriter, field, message string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": map[string]string{field: message}})
+}
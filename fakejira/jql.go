@@ -0,0 +1,176 @@
+package fakejira
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"regexp"
+	"strings"
+)
+
+// matcher reports if an issue matches a JQL clause
+type matcher func(issue jira.Issue) bool
+
+var (
+	orderBy    = regexp.MustCompile(`(?i)\s+order\s+by\s+.*$`)
+	andKeyword = regexp.MustCompile(`(?i)\s+and\s+`)
+	orKeyword  = regexp.MustCompile(`(?i)\s+or\s+`)
+	unescaper  = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`)
+	clause     = regexp.MustCompile(`(?i)^(\w+)\s*(=|!=|\s+not\s+in\s+|\s+in\s+|\s+is\s+not\s+|\s+is\s+)\s*(.+)$`)
+)
+
+// parseJQL parses the subset of JQL that version-meister uses: clauses on project, key, status, fixVersion,
+// component, labels and issuetype, combined with AND. ORDER BY is ignored, other syntax returns an error.
+// Like JIRA, a clause on a key that does not exist returns an error
+func parseJQL(jql string, exists func(key string) bool) (matcher, error) {
+	jql = strings.TrimSpace(orderBy.ReplaceAllString(jql, ""))
+	if jql == "" {
+		return func(jira.Issue) bool { return true }, nil
+	}
+
+	if orKeyword.MatchString(jql) {
+		return nil, fmt.Errorf("Unsupported JQL %v, only AND is supported", jql)
+	}
+
+	var matchers []matcher
+	for _, part := range andKeyword.Split(jql, -1) {
+		m, err := parseClause(strings.TrimSpace(part), exists)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	return func(issue jira.Issue) bool {
+		for _, m := range matchers {
+			if !m(issue) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseClause(text string, exists func(key string) bool) (matcher, error) {
+	parts := clause.FindStringSubmatch(text)
+	if parts == nil {
+		return nil, fmt.Errorf("Unsupported JQL clause %v", text)
+	}
+
+	field := strings.ToLower(parts[1])
+	values, err := fieldValues(field)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := strings.ToLower(strings.Join(strings.Fields(parts[2]), " "))
+	operand := strings.TrimSpace(parts[3])
+
+	switch operator {
+	case "is", "is not":
+		if !strings.EqualFold(operand, "empty") && !strings.EqualFold(operand, "null") {
+			return nil, fmt.Errorf("Unsupported JQL clause %v", text)
+		}
+		empty := operator == "is"
+		return func(issue jira.Issue) bool { return (len(values(issue)) == 0) == empty }, nil
+	case "=", "!=":
+		wanted := []string{unquote(operand)}
+		if err = checkKeys(field, wanted, exists); err != nil {
+			return nil, err
+		}
+		equal := operator == "="
+		return func(issue jira.Issue) bool { return containsAny(values(issue), wanted) == equal }, nil
+	case "in", "not in":
+		if !strings.HasPrefix(operand, "(") || !strings.HasSuffix(operand, ")") {
+			return nil, fmt.Errorf("Unsupported JQL clause %v", text)
+		}
+		var wanted []string
+		for _, value := range strings.Split(operand[1:len(operand)-1], ",") {
+			wanted = append(wanted, unquote(strings.TrimSpace(value)))
+		}
+		if err = checkKeys(field, wanted, exists); err != nil {
+			return nil, err
+		}
+		in := operator == "in"
+		return func(issue jira.Issue) bool { return containsAny(values(issue), wanted) == in }, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported JQL clause %v", text)
+}
+
+// fieldValues returns a function that returns the values of the field of an issue that a clause can match
+func fieldValues(field string) (func(issue jira.Issue) []string, error) {
+	switch field {
+	case "project":
+		return func(issue jira.Issue) []string {
+			return []string{issue.Fields.Project.ID, issue.Fields.Project.Key}
+		}, nil
+	case "key", "issuekey", "id":
+		return func(issue jira.Issue) []string { return []string{issue.Key, issue.ID} }, nil
+	case "status":
+		return func(issue jira.Issue) []string {
+			if issue.Fields.Status == nil {
+				return nil
+			}
+			return []string{issue.Fields.Status.Name, issue.Fields.Status.ID}
+		}, nil
+	case "fixversion":
+		return func(issue jira.Issue) []string {
+			var names []string
+			for _, version := range issue.Fields.FixVersions {
+				names = append(names, version.Name, version.ID)
+			}
+			return names
+		}, nil
+	case "component":
+		return func(issue jira.Issue) []string {
+			var names []string
+			for _, component := range issue.Fields.Components {
+				names = append(names, component.Name, component.ID)
+			}
+			return names
+		}, nil
+	case "labels":
+		return func(issue jira.Issue) []string { return issue.Fields.Labels }, nil
+	case "issuetype", "type":
+		return func(issue jira.Issue) []string {
+			if issue.Fields.IssueType == nil {
+				return nil
+			}
+			return []string{issue.Fields.IssueType.Name, issue.Fields.IssueType.ID}
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported JQL field %v", field)
+}
+
+// checkKeys returns an error for the first key in a clause on the key field that does not exist
+func checkKeys(field string, keys []string, exists func(key string) bool) error {
+	if field != "key" && field != "issuekey" {
+		return nil
+	}
+
+	for _, key := range keys {
+		if !exists(key) {
+			return fmt.Errorf("An issue with key '%v' does not exist for field 'key'.", key)
+		}
+	}
+	return nil
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, value := range values {
+		for _, w := range wanted {
+			if value != "" && strings.EqualFold(value, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return unescaper.Replace(value[1 : len(value)-1])
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package fakejira_test
+
+import (
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func jqlServer() *fakejira.Server {
+	server := fakejira.New()
+	server.AddProject(1337, "AB")
+	server.AddProject(1338, "CD")
+	version := server.AddVersion(jira.Version{Name: "1.0.0", ProjectID: 1337})
+
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project:     jira.Project{ID: "1337"},
+		Status:      &jira.Status{Name: "Ready for Release"},
+		IssueType:   &jira.IssueType{Name: "Bug"},
+		Components:  []jira.Component{{Name: "backend"}},
+		Labels:      []string{"customer"},
+		FixVersions: []jira.Version{version},
+	}})
+	server.AddIssue(jira.Issue{Key: "AB-2", Fields: &jira.IssueFields{
+		Project:   jira.Project{ID: "1337"},
+		Status:    &jira.Status{Name: "Ready for Release"},
+		IssueType: &jira.IssueType{Name: "Story"},
+	}})
+	server.AddIssue(jira.Issue{Key: "CD-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1338"},
+		Status:  &jira.Status{Name: "Done"},
+	}})
+	return server
+}
+
+func TestSearchJQL(t *testing.T) {
+	server := jqlServer()
+	defer server.Close()
+	client := testClient(server)
+
+	tests := []struct {
+		jql  string
+		keys []string
+	}{
+		{"", []string{"AB-1", "AB-2", "CD-1"}},
+		{"project = 1337", []string{"AB-1", "AB-2"}},
+		{`project = AB AND status = "Ready for Release"`, []string{"AB-1", "AB-2"}},
+		{"project = 1337 and fixVersion IS EMPTY", []string{"AB-2"}},
+		{`fixVersion = "1.0.0"`, []string{"AB-1"}},
+		{"fixVersion is not empty", []string{"AB-1"}},
+		{"key in (AB-2, CD-1)", []string{"AB-2", "CD-1"}},
+		{"key not in (AB-2, CD-1)", []string{"AB-1"}},
+		{"component = backend", []string{"AB-1"}},
+		{"labels = customer", []string{"AB-1"}},
+		{"issuetype != Bug AND project = AB ORDER BY key ASC", []string{"AB-2"}},
+		{"status = 'Done'", []string{"CD-1"}},
+	}
+
+	for _, test := range tests {
+		issues, err := client.Search(test.jql)
+		assert.Nil(t, err, test.jql)
+
+		var keys []string
+		for _, issue := range issues {
+			keys = append(keys, issue.Key)
+		}
+		assert.Equal(t, test.keys, keys, test.jql)
+	}
+}
+
+func TestSearchUnsupportedJQLReturnsError(t *testing.T) {
+	server := jqlServer()
+	defer server.Close()
+
+	for _, jql := range []string{"project = AB OR project = CD", "summary ~ release", "fixVersion is 1.0.0"} {
+		_, err := testClient(server).Search(jql)
+		assert.Equal(t, "Search response status is 400", err.Error(), jql)
+	}
+}
+
+func TestSearchUnknownKeyReturnsError(t *testing.T) {
+	server := jqlServer()
+	defer server.Close()
+
+	for _, jql := range []string{"key in (AB-1, UTF-8)", "key = AB-3", "project = AB AND key not in (SHA-256)"} {
+		_, err := testClient(server).Search(jql)
+		assert.Equal(t, "Search response status is 400", err.Error(), jql)
+	}
+}
@@ -0,0 +1,184 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// DefaultKeyPattern matches JIRA issue keys like AB-123
+const DefaultKeyPattern = `[A-Z][A-Z0-9_]+-[0-9]+`
+
+// Commit represents a single commit from the git log
+type Commit struct {
+	SHA     string
+	Refs    []string
+	Message string
+}
+
+// Log runs git log for the commits between the from and to refs in the repository at dir
+func Log(dir, from, to string) ([]Commit, error) {
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("Both from and to refs are required")
+	}
+
+	// A ref like --output=file would be read as an option, git before 2.24 has no --end-of-options to prevent that
+	for _, ref := range []string{from, to} {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("Received incorrect ref %v, refs can not start with -", ref)
+		}
+	}
+
+	cmd := exec.Command("git", "log", "--no-color", "--decorate=short", "--format=medium", from+".."+to, "--")
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %v %v", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseLog(bytes.NewReader(output))
+}
+
+// ParseLog parses the output of git log in the default (medium) format, with or without decorations
+func ParseLog(reader io.Reader) ([]Commit, error) {
+	var commits []Commit
+	var message []string
+
+	flush := func() {
+		if len(commits) > 0 {
+			commits[len(commits)-1].Message = strings.TrimSpace(strings.Join(message, "\n"))
+		}
+		message = nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "commit ") {
+			flush()
+			commits = append(commits, parseCommitLine(line))
+			continue
+		}
+
+		// Message lines are indented by four spaces, header lines like Author and Date are not
+		if strings.HasPrefix(line, "    ") {
+			message = append(message, strings.TrimPrefix(line, "    "))
+		} else if line == "" && len(message) > 0 {
+			message = append(message, "")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+	return commits, nil
+}
+
+// parseCommitLine parses a line like "commit 1a2b3c (HEAD -> feature/AB-1, origin/feature/AB-1)"
+func parseCommitLine(line string) Commit {
+	line = strings.TrimPrefix(line, "commit ")
+	parts := strings.SplitN(line, " ", 2)
+	commit := Commit{SHA: parts[0]}
+
+	if len(parts) == 2 {
+		decoration := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(parts[1]), "("), ")")
+		for _, ref := range strings.Split(decoration, ", ") {
+			ref = strings.TrimPrefix(ref, "HEAD -> ")
+			ref = strings.TrimPrefix(ref, "tag: ")
+			if ref != "" {
+				commit.Refs = append(commit.Refs, ref)
+			}
+		}
+	}
+
+	return commit
+}
+
+// Extractor finds JIRA issue keys in commits
+type Extractor struct {
+	pattern *regexp.Regexp
+}
+
+// NewExtractor returns an Extractor for the provided regular expression, DefaultKeyPattern is used when it is empty
+func NewExtractor(pattern string) (*Extractor, error) {
+	if pattern == "" {
+		pattern = DefaultKeyPattern
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Received incorrect key pattern %v: %v", pattern, err)
+	}
+
+	return &Extractor{pattern: compiled}, nil
+}
+
+// CommitKeys returns the unique issue keys found in the message and branch names of a single commit
+func (e *Extractor) CommitKeys(commit Commit) []string {
+	texts := append([]string{commit.Message}, commit.Refs...)
+	return e.unique(texts)
+}
+
+// Keys returns the unique issue keys found in the commits, in order of first appearance
+func (e *Extractor) Keys(commits []Commit) []string {
+	var texts []string
+	for _, commit := range commits {
+		texts = append(texts, commit.Message)
+		texts = append(texts, commit.Refs...)
+	}
+	return e.unique(texts)
+}
+
+// CommitsByKey returns the SHAs of the commits that reference each issue key
+func (e *Extractor) CommitsByKey(commits []Commit) map[string][]string {
+	shas := make(map[string][]string)
+
+	for _, commit := range commits {
+		for _, key := range e.CommitKeys(commit) {
+			shas[key] = append(shas[key], commit.SHA)
+		}
+	}
+
+	return shas
+}
+
+// ProjectKeys returns the keys that belong to the JIRA project with the provided key. The default pattern also
+// matches text like UTF-8 and SHA-256, which JIRA rejects in a key search
+func ProjectKeys(keys []string, projectKey string) []string {
+	var filtered []string
+	for _, key := range keys {
+		if strings.HasPrefix(strings.ToUpper(key), strings.ToUpper(projectKey)+"-") {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
+func (e *Extractor) unique(texts []string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+
+	for _, text := range texts {
+		for _, key := range e.pattern.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
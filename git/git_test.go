@@ -0,0 +1,142 @@
+package git_test
+
+import (
+	"github.com/marcelblijleven/version-meister/git"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLog = `commit 9fceb02d0ae598e95dc970b74767f19372d61af8 (HEAD -> feature/AB-3-export, origin/feature/AB-3-export)
+Author: Test User <test@example.com>
+Date:   Thu Jan 16 10:00:00 2020 +0100
+
+    AB-2 Fix crash on startup
+
+    Also touches AB-1
+
+commit 2f8d1d2b1a1c8c6c0a9ec8b1f3c56a70a7e2b2f3
+Merge: 1a2b3c4 5d6e7f8
+Author: Test User <test@example.com>
+Date:   Wed Jan 15 10:00:00 2020 +0100
+
+    Merge branch 'feature/CD-10-login'
+`
+
+func TestParseLog(t *testing.T) {
+	commits, err := git.ParseLog(strings.NewReader(testLog))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(commits))
+	assert.Equal(t, "9fceb02d0ae598e95dc970b74767f19372d61af8", commits[0].SHA)
+	assert.Equal(t, []string{"feature/AB-3-export", "origin/feature/AB-3-export"}, commits[0].Refs)
+	assert.Equal(t, "AB-2 Fix crash on startup\n\nAlso touches AB-1", commits[0].Message)
+	assert.Equal(t, "Merge branch 'feature/CD-10-login'", commits[1].Message)
+}
+
+func TestExtractorKeys(t *testing.T) {
+	commits, _ := git.ParseLog(strings.NewReader(testLog))
+	extractor, err := git.NewExtractor("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"AB-2", "AB-1", "AB-3", "CD-10"}, extractor.Keys(commits))
+	assert.Equal(t, []string{"CD-10"}, extractor.CommitKeys(commits[1]))
+}
+
+func TestExtractorCommitsByKey(t *testing.T) {
+	commits, _ := git.ParseLog(strings.NewReader(testLog))
+	extractor, _ := git.NewExtractor("")
+	shas := extractor.CommitsByKey(commits)
+
+	assert.Equal(t, []string{"9fceb02d0ae598e95dc970b74767f19372d61af8"}, shas["AB-2"])
+	assert.Equal(t, []string{"2f8d1d2b1a1c8c6c0a9ec8b1f3c56a70a7e2b2f3"}, shas["CD-10"])
+}
+
+func TestProjectKeys(t *testing.T) {
+	keys := []string{"AB-2", "UTF-8", "ABC-1", "SHA-256", "CD-10", "ab-3"}
+
+	assert.Equal(t, []string{"AB-2", "ab-3"}, git.ProjectKeys(keys, "AB"))
+	assert.Nil(t, git.ProjectKeys(keys, "XY"))
+}
+
+func TestExtractorCustomPattern(t *testing.T) {
+	commits, _ := git.ParseLog(strings.NewReader(testLog))
+	extractor, err := git.NewExtractor(`AB-[0-9]+`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"AB-2", "AB-1", "AB-3"}, extractor.Keys(commits))
+}
+
+func TestNewExtractorInvalidPatternReturnsError(t *testing.T) {
+	extractor, err := git.NewExtractor(`AB-[`)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, extractor)
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	assert.Nil(t, err, string(output))
+}
+
+func TestLogBetweenRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "AB-1 Initial commit")
+	runGit(t, dir, "tag", "v1.0.0")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "AB-2 Add export")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "Fix AB-3 and AB-2")
+	runGit(t, dir, "tag", "v1.1.0")
+
+	commits, err := git.Log(dir, "v1.0.0", "v1.1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(commits))
+
+	extractor, _ := git.NewExtractor("")
+	assert.Equal(t, []string{"AB-3", "AB-2"}, extractor.Keys(commits))
+}
+
+func TestLogUnknownRefReturnsError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	runGit(t, dir, "init", "-q")
+
+	commits, err := git.Log(dir, "v1.0.0", "v1.1.0")
+	assert.NotNil(t, err)
+	assert.Nil(t, commits)
+}
+
+func TestLogRefStartingWithDashReturnsError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	commits, err := git.Log(dir, "--output="+filepath.Join(dir, "log"), "HEAD")
+	assert.EqualError(t, err, "Received incorrect ref --output="+filepath.Join(dir, "log")+", refs can not start with -")
+	assert.Nil(t, commits)
+
+	_, err = os.Stat(filepath.Join(dir, "log"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = git.Log(dir, "v1.0.0", "-p")
+	assert.NotNil(t, err)
+}
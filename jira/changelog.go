@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ChangelogTimeLayout is the layout of the created time of changelog entries
+const ChangelogTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// FixVersionField is the ID of the Fix Version field in changelog items
+const FixVersionField = "fixVersions"
+
+// ChangelogEntry represents a change to one or more fields of a JIRA issue, made by a user at one time
+type ChangelogEntry struct {
+	ID      string          `json:"id"`
+	Author  *User           `json:"author,omitempty"`
+	Created string          `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem represents the change of a single field. From and To hold IDs, like version IDs, and FromString and
+// ToString hold the display values
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype,omitempty"`
+	FieldID    string `json:"fieldId,omitempty"`
+	From       string `json:"from,omitempty"`
+	FromString string `json:"fromString,omitempty"`
+	To         string `json:"to,omitempty"`
+	ToString   string `json:"toString,omitempty"`
+}
+
+// CreatedTime returns the time the change was made
+func (e ChangelogEntry) CreatedTime() (time.Time, error) {
+	return time.Parse(ChangelogTimeLayout, e.Created)
+}
+
+// IsFixVersion reports if the item changed the Fix Version field. JIRA Server does not set the field ID, so the
+// field name is used as well
+func (i ChangelogItem) IsFixVersion() bool {
+	return i.FieldID == FixVersionField || strings.EqualFold(i.Field, "Fix Version")
+}
+
+// FixVersionChange is a fixVersion that was added to or removed from an issue
+type FixVersionChange struct {
+	VersionID string
+	Version   string
+	// Added is true when the version was added and false when it was removed
+	Added   bool
+	Author  *User
+	Created string
+}
+
+// FixVersionHistory is the history of the Fix Version field of an issue, oldest change first
+type FixVersionHistory []FixVersionChange
+
+// NewFixVersionHistory returns the Fix Version changes in the changelog. The entries are ordered by their created
+// time, because JIRA Cloud and Server return them in a different order
+func NewFixVersionHistory(changelog []ChangelogEntry) FixVersionHistory {
+	entries := append([]ChangelogEntry(nil), changelog...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		first, errFirst := entries[i].CreatedTime()
+		second, errSecond := entries[j].CreatedTime()
+		return errFirst == nil && errSecond == nil && first.Before(second)
+	})
+
+	var history FixVersionHistory
+	for _, entry := range entries {
+		for _, item := range entry.Items {
+			if !item.IsFixVersion() {
+				continue
+			}
+
+			change := FixVersionChange{Author: entry.Author, Created: entry.Created}
+			if item.To != "" || item.ToString != "" {
+				change.VersionID, change.Version, change.Added = item.To, item.ToString, true
+			} else {
+				change.VersionID, change.Version = item.From, item.FromString
+			}
+			history = append(history, change)
+		}
+	}
+
+	return history
+}
+
+// Added returns the change that added the version with the provided name, or nil when the issue does not have the
+// version according to the history. When the version was added more than once, the last addition is returned
+func (h FixVersionHistory) Added(name string) *FixVersionChange {
+	var added *FixVersionChange
+	for i := range h {
+		if h[i].Version != name {
+			continue
+		}
+		if h[i].Added {
+			added = &h[i]
+		} else {
+			added = nil
+		}
+	}
+	return added
+}
@@ -0,0 +1,90 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const changelogResponse = `[
+	{
+		"id": "10002",
+		"author": {"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Release Bot"},
+		"created": "2026-10-19T12:00:00.000+0200",
+		"items": [
+			{"field": "Fix Version", "fieldtype": "jira", "fieldId": "fixVersions", "to": "10001", "toString": "1.2.0"},
+			{"field": "status", "fieldtype": "jira", "fieldId": "status", "from": "3", "fromString": "Ready for Release", "to": "10000", "toString": "Done"}
+		]
+	},
+	{
+		"id": "10001",
+		"author": {"name": "jdoe", "displayName": "Jane Doe"},
+		"created": "2026-10-18T09:30:00.000+0000",
+		"items": [
+			{"field": "Fix Version", "fieldtype": "jira", "to": "10000", "toString": "1.1.0"}
+		]
+	},
+	{
+		"id": "10003",
+		"author": {"name": "jdoe", "displayName": "Jane Doe"},
+		"created": "2026-10-19T13:00:00.000+0200",
+		"items": [
+			{"field": "Fix Version", "fieldtype": "jira", "from": "10000", "fromString": "1.1.0"}
+		]
+	}
+]`
+
+func TestChangelogEntryFromJSON(t *testing.T) {
+	var changelog []jira.ChangelogEntry
+	assert.Nil(t, json.Unmarshal([]byte(changelogResponse), &changelog))
+
+	entry := changelog[0]
+	assert.Equal(t, "10002", entry.ID)
+	assert.Equal(t, "Release Bot", entry.Author.DisplayName)
+	assert.Len(t, entry.Items, 2)
+	assert.True(t, entry.Items[0].IsFixVersion())
+	assert.False(t, entry.Items[1].IsFixVersion())
+	assert.Equal(t, "Ready for Release", entry.Items[1].FromString)
+
+	created, err := entry.CreatedTime()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), created.UTC())
+}
+
+func TestFixVersionHistory(t *testing.T) {
+	var changelog []jira.ChangelogEntry
+	assert.Nil(t, json.Unmarshal([]byte(changelogResponse), &changelog))
+
+	history := jira.NewFixVersionHistory(changelog)
+
+	// The entries are ordered by time, the changelog response is not
+	assert.Len(t, history, 3)
+	assert.Equal(t, jira.FixVersionChange{VersionID: "10000", Version: "1.1.0", Added: true, Author: changelog[1].Author, Created: changelog[1].Created}, history[0])
+	assert.Equal(t, jira.FixVersionChange{VersionID: "10001", Version: "1.2.0", Added: true, Author: changelog[0].Author, Created: changelog[0].Created}, history[1])
+	assert.Equal(t, jira.FixVersionChange{VersionID: "10000", Version: "1.1.0", Added: false, Author: changelog[2].Author, Created: changelog[2].Created}, history[2])
+}
+
+func TestFixVersionHistoryAdded(t *testing.T) {
+	var changelog []jira.ChangelogEntry
+	assert.Nil(t, json.Unmarshal([]byte(changelogResponse), &changelog))
+
+	history := jira.NewFixVersionHistory(changelog)
+
+	assert.Equal(t, "Release Bot", history.Added("1.2.0").Author.DisplayName)
+	assert.Nil(t, history.Added("1.1.0"))
+	assert.Nil(t, history.Added("2.0.0"))
+}
+
+func TestFixVersionHistoryAddedAgain(t *testing.T) {
+	jane := &jira.User{Name: "jdoe"}
+	bot := &jira.User{Name: "release-bot"}
+	history := jira.FixVersionHistory{
+		{Version: "1.2.0", Added: true, Author: jane},
+		{Version: "1.2.0", Added: false, Author: bot},
+		{Version: "1.2.0", Added: true, Author: bot},
+	}
+
+	assert.Equal(t, bot, history.Added("1.2.0").Author)
+}
@@ -1,12 +1,28 @@
 package jira
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"github.com/marcelblijleven/version-meister/adf"
 )
 
-// Comment represents a comment on a JIRA issue
+// Comment represents a comment on a JIRA issue. The body is either a plain (wiki markup) string in Body,
+// or an Atlassian Document Format document in Document, which is required by the v3 REST API
 type Comment struct {
-	Body string `json:"body,omitempty"`
+	ID         string        `json:"id,omitempty"`
+	Body       string        `json:"-"`
+	Document   *adf.Document `json:"-"`
+	Author     *User         `json:"author,omitempty"`
+	Visibility *Visibility   `json:"visibility,omitempty"`
+}
+
+// commentJSON is used to marshal the body of a Comment as either a string or a document
+type commentJSON struct {
+	ID         string          `json:"id,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Author     *User           `json:"author,omitempty"`
+	Visibility *Visibility     `json:"visibility,omitempty"`
 }
 
 // NewComment returns a Comment with the provided message as body
@@ -17,3 +33,60 @@ func NewComment(message string) (*Comment, error) {
 
 	return &Comment{Body: message}, nil
 }
+
+// NewDocumentComment returns a Comment with the provided Atlassian Document Format document as body
+func NewDocumentComment(document *adf.Document) (*Comment, error) {
+	if document == nil || len(document.Content) == 0 {
+		return nil, errors.New("Document cannot be empty")
+	}
+
+	return &Comment{Document: document}, nil
+}
+
+// MarshalJSON writes the Document as body when it is set, otherwise the Body string is used
+func (c Comment) MarshalJSON() ([]byte, error) {
+	var body interface{}
+
+	if c.Document != nil {
+		body = c.Document
+	} else if c.Body != "" {
+		body = c.Body
+	}
+
+	raw, err := marshalBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(commentJSON{ID: c.ID, Body: raw, Author: c.Author, Visibility: c.Visibility})
+}
+
+// UnmarshalJSON reads a body that is either a string or an Atlassian Document Format document
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	var raw commentJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = Comment{ID: raw.ID, Author: raw.Author, Visibility: raw.Visibility}
+	body := bytes.TrimSpace(raw.Body)
+
+	if len(body) == 0 || bytes.Equal(body, []byte("null")) {
+		return nil
+	}
+
+	if body[0] == '"' {
+		return json.Unmarshal(body, &c.Body)
+	}
+
+	c.Document = &adf.Document{}
+	return json.Unmarshal(body, c.Document)
+}
+
+func marshalBody(body interface{}) (json.RawMessage, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(body)
+}
@@ -1,6 +1,7 @@
 package jira_test
 
 import (
+	"github.com/marcelblijleven/version-meister/adf"
 	"github.com/marcelblijleven/version-meister/jira"
 	"encoding/json"
 	"errors"
@@ -35,3 +36,57 @@ func TestCommentToJSONConversion(t *testing.T) {
 	assert.Equal(t, expected, result)
 	assert.Nil(t, err)
 }
+
+func TestNewDocumentComment(t *testing.T) {
+	document := adf.NewDocument(adf.Paragraph(adf.Text("This is a test")))
+	comment, err := jira.NewDocumentComment(document)
+
+	assert.Equal(t, document, comment.Document)
+	assert.Nil(t, err)
+}
+
+func TestNewDocumentCommentReturnsErrorWithEmptyDocument(t *testing.T) {
+	comment, err := jira.NewDocumentComment(adf.NewDocument())
+
+	assert.Equal(t, errors.New("Document cannot be empty"), err)
+	assert.Nil(t, comment)
+}
+
+func TestDocumentCommentToJSONConversion(t *testing.T) {
+	expected := "{\"body\":{\"version\":1,\"type\":\"doc\",\"content\":[{\"type\":\"paragraph\"," +
+		"\"content\":[{\"type\":\"text\",\"text\":\"This is a test\"}]}]}}"
+
+	comment, err := jira.NewDocumentComment(adf.NewDocument(adf.Paragraph(adf.Text("This is a test"))))
+	jsonBytes, err := json.Marshal(comment)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
+
+func TestJSONToCommentConversion(t *testing.T) {
+	var stringComment jira.Comment
+	err := json.Unmarshal([]byte("{\"id\":\"1\",\"body\":\"This is a test\"}"), &stringComment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, jira.Comment{ID: "1", Body: "This is a test"}, stringComment)
+
+	var documentComment jira.Comment
+	err = json.Unmarshal([]byte("{\"id\":\"2\",\"body\":{\"version\":1,\"type\":\"doc\",\"content\":[]}}"), &documentComment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2", documentComment.ID)
+	assert.Equal(t, "", documentComment.Body)
+	assert.Equal(t, "doc", documentComment.Document.Type)
+}
+
+func TestRestrictedCommentToJSONConversion(t *testing.T) {
+	expected := "{\"body\":\"Deployed\",\"visibility\":{\"type\":\"role\",\"value\":\"Developers\"}}"
+
+	comment := jira.Comment{Body: "Deployed", Visibility: &jira.Visibility{Type: "role", Value: "Developers"}}
+	jsonBytes, err := json.Marshal(comment)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
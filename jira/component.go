@@ -0,0 +1,7 @@
+package jira
+
+// Component represents a JIRA project component
+type Component struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,21 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponentToJSONConversion(t *testing.T) {
+	component := &jira.Component{
+		ID:   "2000",
+		Name: "Backend",
+	}
+	expected := "{\"id\":\"2000\",\"name\":\"Backend\"}"
+	jsonBytes, err := json.Marshal(component)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
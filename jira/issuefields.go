@@ -2,6 +2,11 @@ package jira
 
 // IssueFields represent the fields property on the JIRA issue
 type IssueFields struct {
-	Project     Project   `json:"project"`
-	FixVersions []Version `json:"fixVersions"`
+	Project     Project     `json:"project"`
+	FixVersions []Version   `json:"fixVersions"`
+	Summary     string      `json:"summary,omitempty"`
+	IssueType   *IssueType  `json:"issuetype,omitempty"`
+	Status      *Status     `json:"status,omitempty"`
+	Components  []Component `json:"components,omitempty"`
+	Labels      []string    `json:"labels,omitempty"`
 }
@@ -0,0 +1,7 @@
+package jira
+
+// IssueType represents the type of a JIRA issue, e.g. Bug or Story
+type IssueType struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
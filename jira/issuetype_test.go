@@ -0,0 +1,21 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIssueTypeToJSONConversion(t *testing.T) {
+	issuetype := &jira.IssueType{
+		ID:   "1",
+		Name: "Bug",
+	}
+	expected := "{\"id\":\"1\",\"name\":\"Bug\"}"
+	jsonBytes, err := json.Marshal(issuetype)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,11 @@
+package jira
+
+import "strings"
+
+// jqlEscaper escapes the characters that end or escape a quoted JQL string
+var jqlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// QuoteJQL returns the value as a quoted JQL string, e.g. for version names in fixVersion clauses
+func QuoteJQL(value string) string {
+	return `"` + jqlEscaper.Replace(value) + `"`
+}
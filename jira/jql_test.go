@@ -0,0 +1,13 @@
+package jira_test
+
+import (
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestQuoteJQL(t *testing.T) {
+	assert.Equal(t, `"1.2.0"`, jira.QuoteJQL("1.2.0"))
+	assert.Equal(t, `"Release \"Phoenix\""`, jira.QuoteJQL(`Release "Phoenix"`))
+	assert.Equal(t, `"backend\\api"`, jira.QuoteJQL(`backend\api`))
+}
@@ -0,0 +1,7 @@
+package jira
+
+// Status represents the workflow status of a JIRA issue
+type Status struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
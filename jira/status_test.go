@@ -0,0 +1,21 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatusToJSONConversion(t *testing.T) {
+	status := &jira.Status{
+		ID:   "10001",
+		Name: "Done",
+	}
+	expected := "{\"id\":\"10001\",\"name\":\"Done\"}"
+	jsonBytes, err := json.Marshal(status)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
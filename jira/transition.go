@@ -0,0 +1,21 @@
+package jira
+
+import (
+	"strings"
+)
+
+// Transition represents a workflow transition that can be performed on a JIRA issue
+type Transition struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name,omitempty"`
+	To   *Status `json:"to,omitempty"`
+}
+
+// Matches reports if the transition has the provided name, or leads to a status with the provided name
+func (t Transition) Matches(name string) bool {
+	if strings.EqualFold(t.Name, name) {
+		return true
+	}
+
+	return t.To != nil && strings.EqualFold(t.To.Name, name)
+}
@@ -0,0 +1,30 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransitionToJSONConversion(t *testing.T) {
+	transition := &jira.Transition{
+		ID:   "31",
+		Name: "Release",
+		To:   &jira.Status{ID: "10001", Name: "Done"},
+	}
+	expected := "{\"id\":\"31\",\"name\":\"Release\",\"to\":{\"id\":\"10001\",\"name\":\"Done\"}}"
+	jsonBytes, err := json.Marshal(transition)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
+
+func TestTransitionMatches(t *testing.T) {
+	transition := jira.Transition{ID: "31", Name: "Release", To: &jira.Status{Name: "Done"}}
+
+	assert.True(t, transition.Matches("release"))
+	assert.True(t, transition.Matches("Done"))
+	assert.False(t, transition.Matches("In Progress"))
+}
@@ -0,0 +1,30 @@
+package jira
+
+// User represents a JIRA user. JIRA Cloud identifies users by AccountID, JIRA Server and Data Center use Name
+type User struct {
+	Self         string `json:"self,omitempty"`
+	AccountID    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// UserField is the field that identifies users in requests
+type UserField int
+
+const (
+	// UserName identifies users by Name, as REST API version 2 does
+	UserName UserField = iota
+	// UserAccountID identifies users by AccountID, the only identifier that REST API version 3 accepts
+	UserAccountID
+)
+
+// Identifier returns the value of the field that identifies the user in requests. JIRA Cloud leaves the Name empty,
+// the AccountID is used instead
+func (u User) Identifier(field UserField) string {
+	if field == UserAccountID || u.Name == "" {
+		return u.AccountID
+	}
+	return u.Name
+}
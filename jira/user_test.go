@@ -0,0 +1,39 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUserToJSONConversion(t *testing.T) {
+	user := &jira.User{
+		AccountID:   "5b10ac8d82e05b22cc7d4ef5",
+		DisplayName: "Test user",
+	}
+	expected := "{\"accountId\":\"5b10ac8d82e05b22cc7d4ef5\",\"displayName\":\"Test user\"}"
+	jsonBytes, err := json.Marshal(user)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
+
+func TestUserIdentifierV2(t *testing.T) {
+	cloudUser := jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5"}
+	serverUser := jira.User{Name: "username", Key: "JIRAUSER10100"}
+	bothUser := jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5", Name: "username"}
+
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", cloudUser.Identifier(jira.UserName))
+	assert.Equal(t, "username", serverUser.Identifier(jira.UserName))
+	assert.Equal(t, "username", bothUser.Identifier(jira.UserName))
+}
+
+func TestUserIdentifierV3(t *testing.T) {
+	cloudUser := jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5"}
+	bothUser := jira.User{AccountID: "5b10ac8d82e05b22cc7d4ef5", Name: "username"}
+
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", cloudUser.Identifier(jira.UserAccountID))
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", bothUser.Identifier(jira.UserAccountID))
+}
@@ -8,7 +8,11 @@ import (
 
 // Version represents a JIRA fixVersion
 type Version struct {
+	ID          string `json:"id,omitempty"`
+	Self        string `json:"self,omitempty"`
 	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Archived    bool   `json:"archived,omitempty"`
 	Released    bool   `json:"released"`
 	ReleaseDate string `json:"releaseDate"`
 	ProjectID   int    `json:"projectId"`
@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Visibility types that restrict who can see a comment
+const (
+	VisibilityRole  = "role"
+	VisibilityGroup = "group"
+)
+
+// Visibility restricts a comment to a project role or a group
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// NewVisibility returns a Visibility for the provided type (role or group) and role or group name
+func NewVisibility(visibilityType, value string) (*Visibility, error) {
+	if visibilityType != VisibilityRole && visibilityType != VisibilityGroup {
+		return nil, fmt.Errorf("Unknown visibility type %v, expected %v or %v", visibilityType, VisibilityRole, VisibilityGroup)
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("Visibility value cannot be empty")
+	}
+
+	return &Visibility{Type: visibilityType, Value: value}, nil
+}
+
+// ParseVisibility returns a Visibility for values like "role:Developers" or "group:jira-administrators"
+func ParseVisibility(value string) (*Visibility, error) {
+	parts := strings.SplitN(value, ":", 2)
+
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Received incorrect visibility %v, expected role:name or group:name", value)
+	}
+
+	return NewVisibility(parts[0], parts[1])
+}
@@ -0,0 +1,52 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewVisibility(t *testing.T) {
+	visibility, err := jira.NewVisibility("role", "Developers")
+
+	assert.Equal(t, &jira.Visibility{Type: "role", Value: "Developers"}, visibility)
+	assert.Nil(t, err)
+}
+
+func TestNewVisibilityUnknownTypeReturnsError(t *testing.T) {
+	visibility, err := jira.NewVisibility("user", "Developers")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, visibility)
+}
+
+func TestNewVisibilityEmptyValueReturnsError(t *testing.T) {
+	visibility, err := jira.NewVisibility("group", "")
+
+	assert.Equal(t, errors.New("Visibility value cannot be empty"), err)
+	assert.Nil(t, visibility)
+}
+
+func TestParseVisibility(t *testing.T) {
+	visibility, err := jira.ParseVisibility("group:jira-administrators")
+
+	assert.Equal(t, &jira.Visibility{Type: "group", Value: "jira-administrators"}, visibility)
+	assert.Nil(t, err)
+
+	visibility, err = jira.ParseVisibility("Developers")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, visibility)
+}
+
+func TestVisibilityToJSONConversion(t *testing.T) {
+	expected := "{\"type\":\"role\",\"value\":\"Developers\"}"
+	visibility, err := jira.NewVisibility("role", "Developers")
+	jsonBytes, err := json.Marshal(visibility)
+	result := string(jsonBytes)
+
+	assert.Equal(t, expected, result)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,261 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Entry is a step that was completed during a run, together with what is needed to undo it
+type Entry struct {
+	Step    string `json:"step"`
+	Action  string `json:"action"`
+	Issue   string `json:"issue,omitempty"`
+	IssueID string `json:"issueId,omitempty"`
+	// VersionID is the ID of the version that the step created
+	VersionID string `json:"versionId,omitempty"`
+	// CommentID is the ID of the comment that the step posted
+	CommentID string `json:"commentId,omitempty"`
+	// FromStatus is the status of the issue before the step transitioned it
+	FromStatus string     `json:"fromStatus,omitempty"`
+	Attempt    int        `json:"attempt"`
+	Completed  time.Time  `json:"completed"`
+	RolledBack *time.Time `json:"rolledBack,omitempty"`
+}
+
+// Attempt is a single execution of a run
+type Attempt struct {
+	Number   int        `json:"number"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Journal records the completed steps of a release run in a JSON file, so an interrupted run can be resumed
+// without repeating the steps that already succeeded
+type Journal struct {
+	RunID   string `json:"runId"`
+	Version string `json:"version,omitempty"`
+	// Issues holds the keys of the issues in the run and the names of the queries that matched them,
+	// so a resumed run still includes issues that no longer match their query
+	Issues   map[string][]string `json:"issues,omitempty"`
+	Attempts []Attempt           `json:"attempts"`
+	Entries  []Entry             `json:"entries"`
+
+	path string
+	done map[string]bool
+}
+
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+var invalidRunIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DefaultDir returns the directory journals are stored in, relative to the working directory
+func DefaultDir() string {
+	return filepath.Join(".version-meister", "runs")
+}
+
+// DefaultRunID returns the run ID for releasing a version, so running the same release again resumes it
+func DefaultRunID(projectID int, version string) string {
+	return invalidRunIDChars.ReplaceAllString(fmt.Sprintf("%v-%v", projectID, version), "-")
+}
+
+// Open returns the journal of the run from the directory, a new journal is returned when the run has none yet
+func Open(dir, runID string) (*Journal, error) {
+	if !runIDPattern.MatchString(runID) {
+		return nil, fmt.Errorf("Invalid run ID %v, only letters, digits, '.', '_' and '-' are allowed", runID)
+	}
+
+	journal := Journal{
+		RunID:  runID,
+		Issues: make(map[string][]string),
+		path:   filepath.Join(dir, runID+".json"),
+		done:   make(map[string]bool),
+	}
+
+	content, err := ioutil.ReadFile(journal.path)
+	if os.IsNotExist(err) {
+		return &journal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(content, &journal); err != nil {
+		return nil, fmt.Errorf("Could not read journal %v: %v", journal.path, err)
+	}
+
+	if journal.Issues == nil {
+		journal.Issues = make(map[string][]string)
+	}
+
+	for _, entry := range journal.Entries {
+		if entry.RolledBack == nil {
+			journal.done[entry.Step] = true
+		}
+	}
+
+	return &journal, nil
+}
+
+// Path returns the file the journal is saved to
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Begin starts a new attempt of the run
+func (j *Journal) Begin() error {
+	j.Attempts = append(j.Attempts, Attempt{Number: len(j.Attempts) + 1, Started: time.Now()})
+	return j.save()
+}
+
+// End finishes the current attempt, err is the error that stopped the attempt or nil when it succeeded
+func (j *Journal) End(err error) error {
+	attempt := j.current()
+	if attempt == nil {
+		return fmt.Errorf("No attempt was started")
+	}
+
+	finished := time.Now()
+	attempt.Finished = &finished
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	return j.save()
+}
+
+// AddIssue records that the issue is part of the run, matched by the named queries
+func (j *Journal) AddIssue(key string, queries []string) {
+	j.Issues[key] = queries
+}
+
+// RemoveIssue removes the issue from the run, later attempts only plan it again when a query still matches it
+func (j *Journal) RemoveIssue(key string) {
+	delete(j.Issues, key)
+}
+
+// Done reports if the step was completed by this or an earlier attempt
+func (j *Journal) Done(step string) bool {
+	return j.done[step]
+}
+
+// Record saves the step of the entry as completed in the current attempt
+func (j *Journal) Record(entry Entry) error {
+	attempt := j.current()
+	if attempt == nil {
+		return fmt.Errorf("No attempt was started")
+	}
+
+	entry.Attempt = attempt.Number
+	entry.Completed = time.Now()
+	j.Entries = append(j.Entries, entry)
+	j.done[entry.Step] = true
+
+	return j.save()
+}
+
+// Completed returns the entries that are not rolled back, most recent first, which is the order to undo them in
+func (j *Journal) Completed() []Entry {
+	var entries []Entry
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		if j.Entries[i].RolledBack == nil {
+			entries = append(entries, j.Entries[i])
+		}
+	}
+	return entries
+}
+
+// MarkRolledBack saves that the step was undone, a later attempt of the run performs the step again
+func (j *Journal) MarkRolledBack(step string) error {
+	now := time.Now()
+	for i := range j.Entries {
+		if j.Entries[i].Step == step && j.Entries[i].RolledBack == nil {
+			j.Entries[i].RolledBack = &now
+		}
+	}
+	delete(j.done, step)
+
+	return j.save()
+}
+
+func (j *Journal) current() *Attempt {
+	if len(j.Attempts) == 0 {
+		return nil
+	}
+	return &j.Attempts[len(j.Attempts)-1]
+}
+
+// save writes the journal to a temporary file first, so an interrupted write does not corrupt the journal
+func (j *Journal) save() error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temp := j.path + ".tmp"
+	if err = ioutil.WriteFile(temp, content, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, j.path)
+}
+
+// Write writes a summary of the steps that were completed across all attempts to the writer
+func (j *Journal) Write(writer io.Writer) error {
+	if _, err := fmt.Fprintf(writer, "Run %v: %v attempt(s), %v step(s) completed\n",
+		j.RunID, len(j.Attempts), len(j.Entries)); err != nil {
+		return err
+	}
+
+	if rolledBack := len(j.Entries) - len(j.Completed()); rolledBack > 0 {
+		if _, err := fmt.Fprintf(writer, "  %v step(s) rolled back\n", rolledBack); err != nil {
+			return err
+		}
+	}
+
+	for _, attempt := range j.Attempts {
+		counts := make(map[string]int)
+		for _, entry := range j.Entries {
+			if entry.Attempt == attempt.Number {
+				counts[entry.Action]++
+			}
+		}
+
+		var actions []string
+		for action := range counts {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+
+		status := "succeeded"
+		if attempt.Finished == nil {
+			status = "did not finish"
+		} else if attempt.Error != "" {
+			status = "failed: " + attempt.Error
+		}
+
+		if _, err := fmt.Fprintf(writer, "  attempt %v (%v) %v\n",
+			attempt.Number, attempt.Started.Format(time.RFC3339), status); err != nil {
+			return err
+		}
+
+		for _, action := range actions {
+			if _, err := fmt.Fprintf(writer, "    %v: %v\n", action, counts[action]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
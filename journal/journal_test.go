@@ -0,0 +1,92 @@
+package journal_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestJournalResume(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	first, err := journal.Open(dir, "1337-1.2.0")
+	assert.Nil(t, err)
+	assert.Nil(t, first.Begin())
+	assert.Nil(t, first.Record(journal.Entry{Step: "assign-version AB-1", Action: "assign-version", Issue: "AB-1"}))
+	first.AddIssue("AB-1", []string{"ready"})
+	assert.Nil(t, first.End(errors.New("Connection reset")))
+
+	second, err := journal.Open(dir, "1337-1.2.0")
+	assert.Nil(t, err)
+	assert.True(t, second.Done("assign-version AB-1"))
+	assert.False(t, second.Done("assign-version AB-2"))
+	assert.Equal(t, map[string][]string{"AB-1": {"ready"}}, second.Issues)
+	assert.Equal(t, filepath.Join(dir, "1337-1.2.0.json"), second.Path())
+
+	assert.Nil(t, second.Begin())
+	assert.Nil(t, second.Record(journal.Entry{Step: "assign-version AB-2", Action: "assign-version", Issue: "AB-2"}))
+	assert.Nil(t, second.Record(journal.Entry{Step: "comment AB-2", Action: "comment", Issue: "AB-2"}))
+	assert.Nil(t, second.End(nil))
+
+	output := new(bytes.Buffer)
+	assert.Nil(t, second.Write(output))
+	assert.Contains(t, output.String(), "Run 1337-1.2.0: 2 attempt(s), 3 step(s) completed\n")
+	assert.Contains(t, output.String(), "failed: Connection reset\n    assign-version: 1\n")
+	assert.Contains(t, output.String(), "succeeded\n    assign-version: 1\n    comment: 1\n")
+}
+
+func TestJournalRecordWithoutAttemptReturnsError(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	j, _ := journal.Open(dir, "run")
+
+	assert.EqualError(t, j.Record(journal.Entry{Step: "create-version", Action: "create-version"}), "No attempt was started")
+}
+
+func TestOpenInvalidRunIDReturnsError(t *testing.T) {
+	_, err := journal.Open("runs", "../escape")
+
+	assert.NotNil(t, err)
+}
+
+func TestDefaultRunID(t *testing.T) {
+	assert.Equal(t, "1337-backend-1.2.0", journal.DefaultRunID(1337, "backend 1.2.0"))
+}
+
+func TestJournalMarkRolledBack(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	j, _ := journal.Open(dir, "run")
+	assert.Nil(t, j.Begin())
+	assert.Nil(t, j.Record(journal.Entry{Step: "create-version", Action: "create-version", VersionID: "20"}))
+	assert.Nil(t, j.Record(journal.Entry{Step: "comment AB-1", Action: "comment", Issue: "AB-1", CommentID: "100"}))
+
+	completed := j.Completed()
+	assert.Equal(t, "comment AB-1", completed[0].Step)
+	assert.Equal(t, "100", completed[0].CommentID)
+	assert.Equal(t, "create-version", completed[1].Step)
+
+	j.AddIssue("AB-1", []string{"ready"})
+	j.RemoveIssue("AB-1")
+	assert.Nil(t, j.MarkRolledBack("comment AB-1"))
+
+	reopened, _ := journal.Open(dir, "run")
+	assert.Empty(t, reopened.Issues)
+	assert.False(t, reopened.Done("comment AB-1"))
+	assert.True(t, reopened.Done("create-version"))
+	assert.Len(t, reopened.Completed(), 1)
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	apiPrefix = regexp.MustCompile(`^/rest/api/[^/]+/`)
+	// idSegment matches path segments with IDs and issue keys, like 10001 or AB-1
+	idSegment = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*-)?[0-9]+$`)
+)
+
+// resources are the path segments that are followed by the ID or key of one of them, like the project key in
+// project/AB/versions
+var resources = map[string]bool{"comment": true, "issue": true, "project": true, "version": true}
+
+// JiraMetrics records the JIRA requests of a client and the issues that runs assigned versions to
+type JiraMetrics struct {
+	requests       *Counter
+	duration       *Histogram
+	retries        *Counter
+	rateLimitWait  *Counter
+	issuesAssigned *Counter
+}
+
+// NewJiraMetrics registers the JIRA metrics in the registry
+func NewJiraMetrics(registry *Registry) *JiraMetrics {
+	return &JiraMetrics{
+		requests: registry.Counter("version_meister_jira_requests_total",
+			"JIRA requests by endpoint, method and status.", "endpoint", "method", "status"),
+		duration: registry.Histogram("version_meister_jira_request_duration_seconds",
+			"Duration of JIRA requests in seconds.", DefaultBuckets, "endpoint", "method"),
+		retries: registry.Counter("version_meister_jira_retries_total",
+			"JIRA requests that were retried by endpoint and status.", "endpoint", "status"),
+		rateLimitWait: registry.Counter("version_meister_jira_rate_limit_wait_seconds_total",
+			"Seconds spent waiting before retrying rate limited JIRA requests."),
+		issuesAssigned: registry.Counter("version_meister_issues_assigned_total",
+			"Issues that a version was assigned to."),
+	}
+}
+
+// Middleware returns client middleware that counts every request and records its duration. Requests that fail
+// without a response have status "error"
+func (m *JiraMetrics) Middleware() api.Middleware {
+	return func(next api.Doer) api.Doer {
+		return api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+
+			endpoint := Endpoint(req.URL.Path)
+			status := "error"
+			if err == nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+
+			m.requests.Inc(endpoint, req.Method, status)
+			m.duration.Observe(time.Since(start).Seconds(), endpoint, req.Method)
+			return resp, err
+		})
+	}
+}
+
+// OnRetry records a retry, use it as api.RetryOptions.OnRetry
+func (m *JiraMetrics) OnRetry(req *http.Request, status int, wait time.Duration) {
+	m.retries.Inc(Endpoint(req.URL.Path), strconv.Itoa(status))
+	if status == http.StatusTooManyRequests {
+		m.rateLimitWait.Add(wait.Seconds())
+	}
+}
+
+// Instrument returns the client with AddVersionToIssue counting the issues that were assigned a version
+func (m *JiraMetrics) Instrument(client api.JiraAPI) api.JiraAPI {
+	return &instrumentedClient{JiraAPI: client, metrics: m}
+}
+
+type instrumentedClient struct {
+	api.JiraAPI
+	metrics *JiraMetrics
+}
+
+// BindContext keeps the instrumentation when the client is bound to a context
+func (c *instrumentedClient) BindContext(ctx context.Context) api.JiraAPI {
+	return &instrumentedClient{JiraAPI: api.WithContext(c.JiraAPI, ctx), metrics: c.metrics}
+}
+
+func (c *instrumentedClient) AddVersionToIssue(issue jira.Issue, version jira.Version) error {
+	err := c.JiraAPI.AddVersionToIssue(issue, version)
+	if err == nil {
+		c.metrics.issuesAssigned.Inc()
+	}
+	return err
+}
+
+// Endpoint returns the path relative to the REST API with IDs and keys replaced by {id}, so every issue, project
+// or version uses the same endpoint label
+func Endpoint(path string) string {
+	segments := strings.Split(strings.Trim(apiPrefix.ReplaceAllString(path, "/"), "/"), "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) || (i > 0 && resources[segments[i-1]]) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
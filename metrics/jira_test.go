@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/metrics"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/rest/api/latest/search":                  "search",
+		"/rest/api/2/issue/AB-1":                   "issue/{id}",
+		"/rest/api/3/issue/10001/comment/10":       "issue/{id}/comment/{id}",
+		"/rest/api/latest/project/1337/versions":   "project/{id}/versions",
+		"/rest/api/latest/version/10002":           "version/{id}",
+		"/rest/api/latest/issue/10001/transitions": "issue/{id}/transitions",
+		"/rest/api/latest/project/AB":              "project/{id}",
+		"/rest/api/latest/project/AB/versions":     "project/{id}/versions",
+		"/rest/api/3/issue/ABC/changelog":          "issue/{id}/changelog",
+		"/rest/api/latest/myself":                  "myself",
+	}
+
+	for path, expected := range tests {
+		assert.Equal(t, expected, metrics.Endpoint(path), path)
+	}
+}
+
+func TestJiraMetrics(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+	server.AddProject(1337, "AB")
+	server.AddVersion(jira.Version{Name: "1.0.0", ProjectID: 1337})
+	issue := server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+	server.FailNext("GET", "issue/AB-1", http.StatusTooManyRequests)
+
+	registry := metrics.NewRegistry()
+	jiraMetrics := metrics.NewJiraMetrics(registry)
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	client.Use(api.Retry(api.RetryOptions{MaxWait: time.Millisecond, OnRetry: jiraMetrics.OnRetry}), jiraMetrics.Middleware())
+	instrumented := jiraMetrics.Instrument(client)
+
+	_, err := instrumented.GetIssue("AB-1")
+	assert.Nil(t, err)
+	assert.Nil(t, instrumented.AddVersionToIssue(issue, jira.Version{Name: "1.0.0"}))
+	assert.NotNil(t, instrumented.AddVersionToIssue(issue, jira.Version{Name: "9.9.9"}))
+
+	buffer := new(bytes.Buffer)
+	registry.Write(buffer)
+	text := buffer.String()
+
+	assert.Contains(t, text, `version_meister_jira_requests_total{endpoint="issue/{id}",method="GET",status="429"} 1`)
+	assert.Contains(t, text, `version_meister_jira_requests_total{endpoint="issue/{id}",method="GET",status="200"} 1`)
+	assert.Contains(t, text, `version_meister_jira_requests_total{endpoint="issue/{id}",method="PUT",status="204"} 1`)
+	assert.Contains(t, text, `version_meister_jira_requests_total{endpoint="issue/{id}",method="PUT",status="400"} 1`)
+	assert.Contains(t, text, `version_meister_jira_request_duration_seconds_count{endpoint="issue/{id}",method="GET"} 2`)
+	assert.Contains(t, text, `version_meister_jira_retries_total{endpoint="issue/{id}",status="429"} 1`)
+	assert.Contains(t, text, "version_meister_jira_rate_limit_wait_seconds_total 0.001\n")
+	assert.Contains(t, text, "version_meister_issues_assigned_total 1\n")
+	assert.False(t, strings.Contains(text, "AB-1"))
+}
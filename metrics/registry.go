@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the content type of the Prometheus text format that Write produces
+const ContentType = "text/plain; version=0.0.4"
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds counters and histograms and writes them in the Prometheus text format
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+// Counter is a counter with labels
+type Counter struct {
+	registry *Registry
+	family   *family
+}
+
+// Histogram counts observations in buckets, it has labels
+type Histogram struct {
+	registry *Registry
+	family   *family
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Counter returns the counter with the name, it is created when the registry does not have it yet
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{registry: r, family: r.family(name, help, "counter", labels, nil)}
+}
+
+// Histogram returns the histogram with the name, it is created when the registry does not have it yet
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{registry: r, family: r.family(name, help, "histogram", labels, buckets)}
+}
+
+func (r *Registry) family(name, help, kind string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind || len(f.labels) != len(labels) {
+			panic(fmt.Sprintf("metric %v is already registered with another type or labels", name))
+		}
+		return f
+	}
+
+	f := &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*series)}
+	r.families[name] = f
+	return f
+}
+
+// get returns the series of the family for the label values, callers hold the lock
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %v expects %v label value(s), got %v", f.name, len(f.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues, counts: make([]uint64, len(f.buckets))}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Add adds the value to the counter with the label values
+func (c *Counter) Add(value float64, labelValues ...string) {
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+	c.family.get(labelValues).value += value
+}
+
+// Inc adds one to the counter with the label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Observe adds the value to the histogram with the label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+
+	s := h.family.get(labelValues)
+	s.value += value
+	s.count++
+	for i, bound := range h.family.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+}
+
+// Write writes all metrics in the Prometheus text format, sorted by name and labels
+func (r *Registry) Write(writer io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := new(bytes.Buffer)
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(buffer, "# HELP %v %v\n# TYPE %v %v\n", f.name, escape(f.help, false), f.name, f.kind)
+
+		var keys []string
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind == "counter" {
+				fmt.Fprintf(buffer, "%v%v %v\n", f.name, labels(f.labels, s.labelValues, ""), formatFloat(s.value))
+				continue
+			}
+
+			for i, bound := range f.buckets {
+				fmt.Fprintf(buffer, "%v_bucket%v %v\n", f.name, labels(f.labels, s.labelValues, formatFloat(bound)), s.counts[i])
+			}
+			fmt.Fprintf(buffer, "%v_bucket%v %v\n", f.name, labels(f.labels, s.labelValues, "+Inf"), s.count)
+			fmt.Fprintf(buffer, "%v_sum%v %v\n", f.name, labels(f.labels, s.labelValues, ""), formatFloat(s.value))
+			fmt.Fprintf(buffer, "%v_count%v %v\n", f.name, labels(f.labels, s.labelValues, ""), s.count)
+		}
+	}
+
+	_, err := buffer.WriteTo(writer)
+	return err
+}
+
+// WriteFile writes the metrics to the file for the textfile collector of the Prometheus node exporter. The file is
+// written to a temporary file first, so the collector never reads a partial file
+func (r *Registry) WriteFile(path string) error {
+	buffer := new(bytes.Buffer)
+	if err := r.Write(buffer); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	temp := path + ".tmp"
+	if err := ioutil.WriteFile(temp, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, path)
+}
+
+// Push sends the metrics to a Pushgateway compatible endpoint, replacing the metrics of the job
+func (r *Registry) Push(gatewayURL, job string) error {
+	buffer := new(bytes.Buffer)
+	if err := r.Write(buffer); err != nil {
+		return err
+	}
+
+	pushURL := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + job
+	req, err := http.NewRequest("PUT", pushURL, buffer)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Push to %v response status is %v", pushURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func labels(names, values []string, le string) string {
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, name, escape(values[i], true)))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf(`le="%v"`, le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(value string, quote bool) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, "\n", `\n`, -1)
+	if quote {
+		value = strings.Replace(value, `"`, `\"`, -1)
+	}
+	return value
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
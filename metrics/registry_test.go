@@ -0,0 +1,117 @@
+package metrics_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/metrics"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testRegistry() *metrics.Registry {
+	registry := metrics.NewRegistry()
+
+	requests := registry.Counter("requests_total", "Requests by status.", "status")
+	requests.Inc("200")
+	requests.Inc("200")
+	requests.Add(0.5, "500")
+
+	duration := registry.Histogram("duration_seconds", "Duration.", []float64{0.1, 1})
+	duration.Observe(0.05)
+	duration.Observe(0.5)
+	duration.Observe(2)
+
+	return registry
+}
+
+const expectedMetrics = `# HELP duration_seconds Duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{le="0.1"} 1
+duration_seconds_bucket{le="1"} 2
+duration_seconds_bucket{le="+Inf"} 3
+duration_seconds_sum 2.55
+duration_seconds_count 3
+# HELP requests_total Requests by status.
+# TYPE requests_total counter
+requests_total{status="200"} 2
+requests_total{status="500"} 0.5
+`
+
+func TestRegistryWrite(t *testing.T) {
+	buffer := new(bytes.Buffer)
+
+	err := testRegistry().Write(buffer)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedMetrics, buffer.String())
+}
+
+func TestRegistryEscapesLabelValues(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("total", "Help with \\ and\nnewline.", "name").Inc("a \"quoted\"\nvalue")
+	buffer := new(bytes.Buffer)
+
+	registry.Write(buffer)
+
+	assert.Equal(t, "# HELP total Help with \\\\ and\\nnewline.\n# TYPE total counter\n"+
+		"total{name=\"a \\\"quoted\\\"\\nvalue\"} 1\n", buffer.String())
+}
+
+func TestRegistryReturnsExistingMetric(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("total", "Total.").Inc()
+	registry.Counter("total", "Total.").Inc()
+	buffer := new(bytes.Buffer)
+
+	registry.Write(buffer)
+
+	assert.Equal(t, "# HELP total Total.\n# TYPE total counter\ntotal 2\n", buffer.String())
+	assert.Panics(t, func() { registry.Histogram("total", "Total.", nil) })
+	assert.Panics(t, func() { registry.Counter("total", "Total.").Inc("unexpected") })
+}
+
+func TestRegistryWriteFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "metrics")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "textfile", "version_meister.prom")
+
+	err := testRegistry().WriteFile(path)
+
+	assert.Nil(t, err)
+	content, _ := ioutil.ReadFile(path)
+	assert.Equal(t, expectedMetrics, string(content))
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRegistryPush(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "/metrics/job/version_meister", req.URL.Path)
+		assert.Equal(t, metrics.ContentType, req.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, expectedMetrics, string(body))
+		writer.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	err := testRegistry().Push(server.URL+"/", "version_meister")
+
+	assert.Nil(t, err)
+}
+
+func TestRegistryPushReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := testRegistry().Push(server.URL, "version_meister")
+
+	assert.Equal(t, "Push to "+server.URL+"/metrics/job/version_meister response status is 400", err.Error())
+}
@@ -0,0 +1,143 @@
+package notes
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"sort"
+	"strings"
+)
+
+// Grouping options for release notes
+const (
+	GroupByType      = "type"
+	GroupByComponent = "component"
+	GroupByLabel     = "label"
+)
+
+// otherGroup is used for issues that have no value for the selected grouping
+const otherGroup = "Other"
+
+// ReleaseNotes contains all data that is passed to a release notes template
+type ReleaseNotes struct {
+	Version     string
+	ReleaseDate string
+	Groups      []Group
+}
+
+// Group is a named collection of release note entries
+type Group struct {
+	Name    string
+	Entries []Entry
+}
+
+// Entry represents a single JIRA issue in the release notes
+type Entry struct {
+	Key     string
+	Summary string
+	Type    string
+	Status  string
+	URL     string
+}
+
+// NewReleaseNotes returns ReleaseNotes for the provided issues, grouped by issue type, component or label.
+// The baseURL is used to create links to the issues and may be empty
+func NewReleaseNotes(version, releaseDate, baseURL, groupBy string, issues []jira.Issue) (*ReleaseNotes, error) {
+	if version == "" {
+		return nil, fmt.Errorf("Version cannot be empty")
+	}
+
+	groups := make(map[string][]Entry)
+
+	for _, issue := range issues {
+		names, err := groupNames(issue, groupBy)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entry := newEntry(issue, baseURL)
+		for _, name := range names {
+			groups[name] = append(groups[name], entry)
+		}
+	}
+
+	releaseNotes := ReleaseNotes{
+		Version:     version,
+		ReleaseDate: releaseDate,
+	}
+
+	for name, entries := range groups {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Key < entries[j].Key
+		})
+		releaseNotes.Groups = append(releaseNotes.Groups, Group{Name: name, Entries: entries})
+	}
+
+	sort.Slice(releaseNotes.Groups, func(i, j int) bool {
+		// Always put the catch-all group last
+		if releaseNotes.Groups[i].Name == otherGroup {
+			return false
+		}
+		if releaseNotes.Groups[j].Name == otherGroup {
+			return true
+		}
+		return releaseNotes.Groups[i].Name < releaseNotes.Groups[j].Name
+	})
+
+	return &releaseNotes, nil
+}
+
+func groupNames(issue jira.Issue, groupBy string) ([]string, error) {
+	var names []string
+	fields := issue.Fields
+
+	switch groupBy {
+	case GroupByType, "":
+		if fields != nil && fields.IssueType != nil && fields.IssueType.Name != "" {
+			names = append(names, fields.IssueType.Name)
+		}
+	case GroupByComponent:
+		if fields != nil {
+			for _, component := range fields.Components {
+				names = append(names, component.Name)
+			}
+		}
+	case GroupByLabel:
+		if fields != nil {
+			names = append(names, fields.Labels...)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown grouping %v, expected one of %v, %v or %v", groupBy, GroupByType, GroupByComponent, GroupByLabel)
+	}
+
+	if len(names) == 0 {
+		names = append(names, otherGroup)
+	}
+
+	return names, nil
+}
+
+func newEntry(issue jira.Issue, baseURL string) Entry {
+	entry := Entry{
+		Key:     issue.Key,
+		Summary: issue.Summary,
+	}
+
+	if fields := issue.Fields; fields != nil {
+		if fields.Summary != "" {
+			entry.Summary = fields.Summary
+		}
+		if fields.IssueType != nil {
+			entry.Type = fields.IssueType.Name
+		}
+		if fields.Status != nil {
+			entry.Status = fields.Status.Name
+		}
+	}
+
+	if baseURL != "" {
+		entry.URL = fmt.Sprintf("%s/browse/%s", strings.TrimSuffix(baseURL, "/"), issue.Key)
+	}
+
+	return entry
+}
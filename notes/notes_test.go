@@ -0,0 +1,85 @@
+package notes_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/notes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testIssues() []jira.Issue {
+	return []jira.Issue{
+		{
+			Key: "AB-2",
+			Fields: &jira.IssueFields{
+				Summary:    "Fix login",
+				IssueType:  &jira.IssueType{Name: "Bug"},
+				Components: []jira.Component{{Name: "Backend"}},
+				Labels:     []string{"security"},
+			},
+		},
+		{
+			Key: "AB-1",
+			Fields: &jira.IssueFields{
+				Summary:    "Add export",
+				IssueType:  &jira.IssueType{Name: "Story"},
+				Components: []jira.Component{{Name: "Backend"}, {Name: "Frontend"}},
+			},
+		},
+		{
+			Key: "AB-3",
+			Fields: &jira.IssueFields{
+				Summary:   "Fix typo",
+				IssueType: &jira.IssueType{Name: "Bug"},
+			},
+		},
+	}
+}
+
+func TestNewReleaseNotesGroupsByType(t *testing.T) {
+	releaseNotes, err := notes.NewReleaseNotes("1.0.0", "2020-01-16", "https://jira.example.com/", notes.GroupByType, testIssues())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(releaseNotes.Groups))
+	assert.Equal(t, "Bug", releaseNotes.Groups[0].Name)
+	assert.Equal(t, "AB-2", releaseNotes.Groups[0].Entries[0].Key)
+	assert.Equal(t, "AB-3", releaseNotes.Groups[0].Entries[1].Key)
+	assert.Equal(t, "https://jira.example.com/browse/AB-2", releaseNotes.Groups[0].Entries[0].URL)
+	assert.Equal(t, "Story", releaseNotes.Groups[1].Name)
+}
+
+func TestNewReleaseNotesGroupsByComponent(t *testing.T) {
+	releaseNotes, err := notes.NewReleaseNotes("1.0.0", "", "", notes.GroupByComponent, testIssues())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(releaseNotes.Groups))
+	assert.Equal(t, "Backend", releaseNotes.Groups[0].Name)
+	assert.Equal(t, 2, len(releaseNotes.Groups[0].Entries))
+	assert.Equal(t, "Frontend", releaseNotes.Groups[1].Name)
+	assert.Equal(t, "Other", releaseNotes.Groups[2].Name)
+	assert.Equal(t, "AB-3", releaseNotes.Groups[2].Entries[0].Key)
+}
+
+func TestNewReleaseNotesGroupsByLabel(t *testing.T) {
+	releaseNotes, err := notes.NewReleaseNotes("1.0.0", "", "", notes.GroupByLabel, testIssues())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(releaseNotes.Groups))
+	assert.Equal(t, "security", releaseNotes.Groups[0].Name)
+	assert.Equal(t, "Other", releaseNotes.Groups[1].Name)
+}
+
+func TestNewReleaseNotesUnknownGroupingReturnsError(t *testing.T) {
+	releaseNotes, err := notes.NewReleaseNotes("1.0.0", "", "", "priority", testIssues())
+
+	assert.NotNil(t, err)
+	assert.Nil(t, releaseNotes)
+}
+
+func TestNewReleaseNotesEmptyVersionReturnsError(t *testing.T) {
+	releaseNotes, err := notes.NewReleaseNotes("", "", "", notes.GroupByType, testIssues())
+
+	assert.Equal(t, errors.New("Version cannot be empty"), err)
+	assert.Nil(t, releaseNotes)
+}
@@ -0,0 +1,69 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"text/template"
+)
+
+// Supported output formats for release notes
+const (
+	FormatMarkdown   = "markdown"
+	FormatHTML       = "html"
+	FormatText       = "text"
+	FormatConfluence = "confluence"
+)
+
+var defaultTemplates = map[string]string{
+	FormatMarkdown: `# Release notes {{.Version}}{{if .ReleaseDate}} ({{.ReleaseDate}}){{end}}
+{{range .Groups}}
+## {{.Name}}
+
+{{range .Entries}}- {{if .URL}}[{{.Key}}]({{.URL}}){{else}}{{.Key}}{{end}} {{.Summary}}
+{{end}}{{end}}`,
+	FormatHTML: `<h1>Release notes {{html .Version}}{{if .ReleaseDate}} ({{html .ReleaseDate}}){{end}}</h1>
+{{range .Groups}}<h2>{{html .Name}}</h2>
+<ul>
+{{range .Entries}}<li>{{if .URL}}<a href="{{html .URL}}">{{html .Key}}</a>{{else}}{{html .Key}}{{end}} {{html .Summary}}</li>
+{{end}}</ul>
+{{end}}`,
+	FormatText: `Release notes {{.Version}}{{if .ReleaseDate}} ({{.ReleaseDate}}){{end}}
+{{range .Groups}}
+{{.Name}}
+{{range .Entries}}  * {{.Key}} {{.Summary}}
+{{end}}{{end}}`,
+	FormatConfluence: `h1. Release notes {{.Version}}{{if .ReleaseDate}} ({{.ReleaseDate}}){{end}}
+{{range .Groups}}
+h2. {{.Name}}
+{{range .Entries}}* {{if .URL}}[{{.Key}}|{{.URL}}]{{else}}{{.Key}}{{end}} {{.Summary}}
+{{end}}{{end}}`,
+}
+
+// Template returns the default template for the provided format
+func Template(format string) (*template.Template, error) {
+	text, ok := defaultTemplates[format]
+
+	if !ok {
+		return nil, fmt.Errorf("Unknown format %v, expected one of %v, %v, %v or %v",
+			format, FormatMarkdown, FormatHTML, FormatText, FormatConfluence)
+	}
+
+	return template.New(format).Parse(text)
+}
+
+// TemplateFromFile returns a user provided template that overrides the default templates
+func TemplateFromFile(path string) (*template.Template, error) {
+	text, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return template.New(path).Parse(string(text))
+}
+
+// Render writes the release notes to the writer using the provided template
+func Render(writer io.Writer, tmpl *template.Template, releaseNotes *ReleaseNotes) error {
+	return tmpl.Execute(writer, releaseNotes)
+}
@@ -0,0 +1,92 @@
+package notes_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/notes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testReleaseNotes() *notes.ReleaseNotes {
+	issues := []jira.Issue{
+		{
+			Key: "AB-1",
+			Fields: &jira.IssueFields{
+				Summary:   "Escape <b>tags</b>",
+				IssueType: &jira.IssueType{Name: "Bug"},
+			},
+		},
+	}
+	releaseNotes, _ := notes.NewReleaseNotes("1.0.0", "2020-01-16", "https://jira.example.com", notes.GroupByType, issues)
+	return releaseNotes
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	expected := "# Release notes 1.0.0 (2020-01-16)\n\n## Bug\n\n" +
+		"- [AB-1](https://jira.example.com/browse/AB-1) Escape <b>tags</b>\n"
+
+	tmpl, err := notes.Template(notes.FormatMarkdown)
+	assert.Nil(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = notes.Render(buffer, tmpl, testReleaseNotes())
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestRenderHTMLEscapesValues(t *testing.T) {
+	tmpl, err := notes.Template(notes.FormatHTML)
+	assert.Nil(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = notes.Render(buffer, tmpl, testReleaseNotes())
+
+	assert.Nil(t, err)
+	assert.Contains(t, buffer.String(), "<h2>Bug</h2>")
+	assert.Contains(t, buffer.String(), "Escape &lt;b&gt;tags&lt;/b&gt;")
+}
+
+func TestRenderConfluence(t *testing.T) {
+	expected := "h1. Release notes 1.0.0 (2020-01-16)\n\nh2. Bug\n" +
+		"* [AB-1|https://jira.example.com/browse/AB-1] Escape <b>tags</b>\n"
+
+	tmpl, err := notes.Template(notes.FormatConfluence)
+	assert.Nil(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = notes.Render(buffer, tmpl, testReleaseNotes())
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestTemplateUnknownFormatReturnsError(t *testing.T) {
+	tmpl, err := notes.Template("pdf")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, tmpl)
+}
+
+func TestTemplateFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notes")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "notes.tmpl")
+	err = ioutil.WriteFile(path, []byte("{{.Version}}:{{range .Groups}}{{len .Entries}}{{end}}"), 0644)
+	assert.Nil(t, err)
+
+	tmpl, err := notes.TemplateFromFile(path)
+	assert.Nil(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = notes.Render(buffer, tmpl, testReleaseNotes())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0:1", buffer.String())
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"gopkg.in/yaml.v2"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Supported output formats, a Go template is selected with "template=" followed by the template text
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatCSV      = "csv"
+	templatePrefix = "template="
+)
+
+// Result is the structured result of a command
+type Result struct {
+	Command string         `json:"command" yaml:"command"`
+	Message string         `json:"message,omitempty" yaml:"message,omitempty"`
+	Version *VersionResult `json:"version,omitempty" yaml:"version,omitempty"`
+	Issues  []IssueResult  `json:"issues" yaml:"issues"`
+	Errors  []string       `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// VersionResult describes the version a command worked on
+type VersionResult struct {
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	ProjectID   int    `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty" yaml:"releaseDate,omitempty"`
+	Created     bool   `json:"created" yaml:"created"`
+	Released    bool   `json:"released" yaml:"released"`
+}
+
+// IssueResult describes an issue that a command affected, Action tells what happened to it
+type IssueResult struct {
+	Key     string `json:"key" yaml:"key"`
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Type    string `json:"type,omitempty" yaml:"type,omitempty"`
+	Status  string `json:"status,omitempty" yaml:"status,omitempty"`
+	Action  string `json:"action,omitempty" yaml:"action,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// NewVersionResult returns the result for the version
+func NewVersionResult(version jira.Version, created bool) *VersionResult {
+	return &VersionResult{
+		ID:          version.ID,
+		Name:        version.Name,
+		ProjectID:   version.ProjectID,
+		ReleaseDate: version.ReleaseDate,
+		Created:     created,
+		Released:    version.Released,
+	}
+}
+
+// NewIssueResult returns the result for the issue with the provided action
+func NewIssueResult(issue jira.Issue, action string) IssueResult {
+	result := IssueResult{Key: issue.Key, Summary: issue.Summary, Action: action}
+
+	if issue.Fields == nil {
+		return result
+	}
+
+	if issue.Fields.Summary != "" {
+		result.Summary = issue.Fields.Summary
+	}
+	if issue.Fields.IssueType != nil {
+		result.Type = issue.Fields.IssueType.Name
+	}
+	if issue.Fields.Status != nil {
+		result.Status = issue.Fields.Status.Name
+	}
+
+	return result
+}
+
+// AddIssues adds a result with the action for every issue
+func (r *Result) AddIssues(issues []jira.Issue, action string) {
+	for _, issue := range issues {
+		r.Issues = append(r.Issues, NewIssueResult(issue, action))
+	}
+}
+
+// AddError adds the error to the result
+func (r *Result) AddError(err error) {
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// Printer writes results in the selected format
+type Printer struct {
+	format string
+	tmpl   *template.Template
+}
+
+// New returns a Printer for the format: table, json, yaml, csv or template=<Go template>
+func New(format string) (*Printer, error) {
+	if strings.HasPrefix(format, templatePrefix) {
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, templatePrefix))
+		if err != nil {
+			return nil, err
+		}
+		return &Printer{format: templatePrefix, tmpl: tmpl}, nil
+	}
+
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML, FormatCSV:
+		return &Printer{format: format}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown output format %v, expected one of %v, %v, %v, %v or %vtext",
+		format, FormatTable, FormatJSON, FormatYAML, FormatCSV, templatePrefix)
+}
+
+// Structured reports if the output is meant for machines. Commands then write their progress messages to stderr,
+// so only the result is written to stdout
+func (p *Printer) Structured() bool {
+	return p.format != FormatTable
+}
+
+// Print writes the result to the writer
+func (p *Printer) Print(writer io.Writer, result *Result) error {
+	switch p.format {
+	case FormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case FormatYAML:
+		content, err := yaml.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(content)
+		return err
+	case FormatCSV:
+		return printCSV(writer, result)
+	case templatePrefix:
+		return p.tmpl.Execute(writer, result)
+	}
+
+	return printTable(writer, result)
+}
+
+func printCSV(writer io.Writer, result *Result) error {
+	csvWriter := csv.NewWriter(writer)
+	version := ""
+	if result.Version != nil {
+		version = result.Version.Name
+	}
+
+	if err := csvWriter.Write([]string{"version", "key", "summary", "type", "status", "action", "error"}); err != nil {
+		return err
+	}
+
+	for _, issue := range result.Issues {
+		record := []string{version, issue.Key, issue.Summary, issue.Type, issue.Status, issue.Action, issue.Error}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func printTable(writer io.Writer, result *Result) error {
+	if result.Message != "" {
+		if _, err := fmt.Fprintln(writer, result.Message); err != nil {
+			return err
+		}
+	}
+
+	if version := result.Version; version != nil {
+		state := "existing"
+		if version.Created {
+			state = "created"
+		}
+		if version.Released {
+			state += ", released"
+		}
+
+		if _, err := fmt.Fprintf(writer, "Version %v (project %v, %v)\n", version.Name, version.ProjectID, state); err != nil {
+			return err
+		}
+	}
+
+	if len(result.Issues) > 0 {
+		table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "KEY\tTYPE\tSTATUS\tACTION\tSUMMARY")
+		for _, issue := range result.Issues {
+			action := issue.Action
+			if issue.Error != "" {
+				action += ": " + issue.Error
+			}
+			fmt.Fprintf(table, "%v\t%v\t%v\t%v\t%v\n", issue.Key, issue.Type, issue.Status, action, issue.Summary)
+		}
+
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	for _, message := range result.Errors {
+		if _, err := fmt.Fprintln(writer, "Error:", message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
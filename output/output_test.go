@@ -0,0 +1,87 @@
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/output"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testResult() *output.Result {
+	version := jira.Version{ID: "20", Name: "1.2.0", ReleaseDate: "2020-06-01", ProjectID: 1337}
+	result := &output.Result{Command: "create", Version: output.NewVersionResult(version, true)}
+	result.AddIssues([]jira.Issue{{
+		Key: "AB-1",
+		Fields: &jira.IssueFields{
+			Summary:   "Fix login, again",
+			IssueType: &jira.IssueType{Name: "Bug"},
+			Status:    &jira.Status{Name: "Done"},
+		},
+	}}, "assigned")
+	return result
+}
+
+func render(t *testing.T, format string, result *output.Result) string {
+	printer, err := output.New(format)
+	assert.Nil(t, err)
+
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, printer.Print(buffer, result))
+	return buffer.String()
+}
+
+func TestPrintJSON(t *testing.T) {
+	expected := `{"command":"create","version":{"id":"20","name":"1.2.0","projectId":1337,"releaseDate":"2020-06-01",` +
+		`"created":true,"released":false},"issues":[{"key":"AB-1","summary":"Fix login, again","type":"Bug",` +
+		`"status":"Done","action":"assigned"}]}`
+
+	assert.JSONEq(t, expected, render(t, output.FormatJSON, testResult()))
+}
+
+func TestPrintYAML(t *testing.T) {
+	result := render(t, output.FormatYAML, testResult())
+
+	assert.Contains(t, result, "command: create\n")
+	assert.Contains(t, result, "- key: AB-1\n  summary: Fix login, again\n  type: Bug\n  status: Done\n  action: assigned\n")
+}
+
+func TestPrintCSV(t *testing.T) {
+	expected := "version,key,summary,type,status,action,error\n" +
+		"1.2.0,AB-1,\"Fix login, again\",Bug,Done,assigned,\n"
+
+	assert.Equal(t, expected, render(t, output.FormatCSV, testResult()))
+}
+
+func TestPrintTable(t *testing.T) {
+	result := testResult()
+	result.AddError(errors.New("Could not comment AB-2"))
+
+	expected := "Version 1.2.0 (project 1337, created)\n" +
+		"KEY   TYPE  STATUS  ACTION    SUMMARY\n" +
+		"AB-1  Bug   Done    assigned  Fix login, again\n" +
+		"Error: Could not comment AB-2\n"
+
+	assert.Equal(t, expected, render(t, output.FormatTable, result))
+}
+
+func TestPrintTemplate(t *testing.T) {
+	result := render(t, "template={{range .Issues}}{{.Key}} {{.Status}}{{end}}", testResult())
+
+	assert.Equal(t, "AB-1 Done", result)
+}
+
+func TestNewUnknownFormatReturnsError(t *testing.T) {
+	_, err := output.New("xml")
+
+	assert.NotNil(t, err)
+}
+
+func TestStructured(t *testing.T) {
+	table, _ := output.New(output.FormatTable)
+	json, _ := output.New(output.FormatJSON)
+
+	assert.False(t, table.Structured())
+	assert.True(t, json.Structured())
+}
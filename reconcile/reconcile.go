@@ -0,0 +1,133 @@
+package reconcile
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io"
+	"sort"
+)
+
+// LookupFunc returns the issue with the provided key, or nil when the issue does not exist
+type LookupFunc func(key string) (*jira.Issue, error)
+
+// Report describes the drift between the issues referenced in git and the issues in a JIRA version
+type Report struct {
+	Version string
+	// MissingInJira contains issues that are referenced in git, but do not have the version as fixVersion
+	MissingInJira []jira.Issue
+	// MissingInGit contains issues that have the version as fixVersion, but are not referenced in git
+	MissingInGit []jira.Issue
+	// Unknown contains keys that are referenced in git, but do not exist in JIRA
+	Unknown []string
+}
+
+// Compare returns a Report of the differences between the keys found in git and the issues in the version.
+// The lookup function is used to determine if keys that are missing from the version exist at all
+func Compare(version string, gitKeys []string, versionIssues []jira.Issue, lookup LookupFunc) (*Report, error) {
+	report := Report{Version: version}
+	inGit := make(map[string]bool)
+
+	for _, key := range gitKeys {
+		inGit[key] = true
+	}
+
+	inVersion := make(map[string]bool)
+	for _, issue := range versionIssues {
+		inVersion[issue.Key] = true
+
+		if !inGit[issue.Key] {
+			report.MissingInGit = append(report.MissingInGit, issue)
+		}
+	}
+
+	for _, key := range gitKeys {
+		if inVersion[key] {
+			continue
+		}
+
+		issue, err := lookup(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if issue == nil {
+			report.Unknown = append(report.Unknown, key)
+			continue
+		}
+
+		report.MissingInJira = append(report.MissingInJira, *issue)
+	}
+
+	sort.Slice(report.MissingInGit, func(i, j int) bool {
+		return report.MissingInGit[i].Key < report.MissingInGit[j].Key
+	})
+	sort.Slice(report.MissingInJira, func(i, j int) bool {
+		return report.MissingInJira[i].Key < report.MissingInJira[j].Key
+	})
+	sort.Strings(report.Unknown)
+
+	return &report, nil
+}
+
+// HasDrift reports if git and JIRA disagree about the contents of the version
+func (r *Report) HasDrift() bool {
+	return len(r.MissingInJira) > 0 || len(r.MissingInGit) > 0 || len(r.Unknown) > 0
+}
+
+// Fix calls add for every issue that is referenced in git but is missing the version in JIRA
+func (r *Report) Fix(add func(issue jira.Issue) error) error {
+	for _, issue := range r.MissingInJira {
+		if err := add(issue); err != nil {
+			return fmt.Errorf("Could not fix %v: %w", issue.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// Write writes a human readable version of the report to the writer
+func (r *Report) Write(writer io.Writer) error {
+	if !r.HasDrift() {
+		_, err := fmt.Fprintf(writer, "Version %v matches git history\n", r.Version)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(writer, "Version %v does not match git history\n", r.Version); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title string
+		keys  []string
+	}{
+		{"Missing in JIRA (in git, without fixVersion)", issueKeys(r.MissingInJira)},
+		{"Missing in git (fixVersion set, not in git)", issueKeys(r.MissingInGit)},
+		{"Unknown issue keys", r.Unknown},
+	}
+
+	for _, section := range sections {
+		if len(section.keys) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(writer, "\n%v:\n", section.title); err != nil {
+			return err
+		}
+
+		for _, key := range section.keys {
+			if _, err := fmt.Fprintf(writer, "  %v\n", key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func issueKeys(issues []jira.Issue) []string {
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	return keys
+}
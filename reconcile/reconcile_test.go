@@ -0,0 +1,79 @@
+package reconcile_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/reconcile"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testLookup(key string) (*jira.Issue, error) {
+	if key == "AB-404" {
+		return nil, nil
+	}
+	return &jira.Issue{Key: key}, nil
+}
+
+func TestCompare(t *testing.T) {
+	gitKeys := []string{"AB-3", "AB-1", "AB-404", "AB-2"}
+	versionIssues := []jira.Issue{{Key: "AB-1"}, {Key: "AB-5"}}
+
+	report, err := reconcile.Compare("1.0.0", gitKeys, versionIssues, testLookup)
+
+	assert.Nil(t, err)
+	assert.True(t, report.HasDrift())
+	assert.Equal(t, []jira.Issue{{Key: "AB-2"}, {Key: "AB-3"}}, report.MissingInJira)
+	assert.Equal(t, []jira.Issue{{Key: "AB-5"}}, report.MissingInGit)
+	assert.Equal(t, []string{"AB-404"}, report.Unknown)
+}
+
+func TestCompareWithoutDrift(t *testing.T) {
+	report, err := reconcile.Compare("1.0.0", []string{"AB-1"}, []jira.Issue{{Key: "AB-1"}}, testLookup)
+
+	assert.Nil(t, err)
+	assert.False(t, report.HasDrift())
+
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, report.Write(buffer))
+	assert.Equal(t, "Version 1.0.0 matches git history\n", buffer.String())
+}
+
+func TestCompareReturnsLookupError(t *testing.T) {
+	lookup := func(key string) (*jira.Issue, error) {
+		return nil, errors.New("Lookup failed")
+	}
+
+	report, err := reconcile.Compare("1.0.0", []string{"AB-1"}, nil, lookup)
+
+	assert.Equal(t, errors.New("Lookup failed"), err)
+	assert.Nil(t, report)
+}
+
+func TestReportFix(t *testing.T) {
+	report, _ := reconcile.Compare("1.0.0", []string{"AB-1", "AB-2"}, nil, testLookup)
+
+	var fixed []string
+	err := report.Fix(func(issue jira.Issue) error {
+		fixed = append(fixed, issue.Key)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"AB-1", "AB-2"}, fixed)
+}
+
+func TestReportWrite(t *testing.T) {
+	report, _ := reconcile.Compare("1.0.0", []string{"AB-2", "AB-404"}, []jira.Issue{{Key: "AB-5"}}, testLookup)
+	expected := "Version 1.0.0 does not match git history\n" +
+		"\nMissing in JIRA (in git, without fixVersion):\n  AB-2\n" +
+		"\nMissing in git (fixVersion set, not in git):\n  AB-5\n" +
+		"\nUnknown issue keys:\n  AB-404\n"
+
+	buffer := new(bytes.Buffer)
+	err := report.Write(buffer)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, buffer.String())
+}
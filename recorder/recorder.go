@@ -0,0 +1,259 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode selects if a Recorder sends requests to JIRA and records them, or replays recorded responses
+type Mode int
+
+// Recorder modes
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+// ScrubbedEmail replaces email addresses in recorded fixtures
+const ScrubbedEmail = "user@example.com"
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// recordedHeaders are the response headers that are kept in fixtures, others like cookies are dropped
+var recordedHeaders = []string{"Content-Type"}
+
+// Request is the recorded part of a request, the host and credentials are not recorded
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded part of a response
+type Response struct {
+	StatusCode int               `json:"statusCode"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request and the response JIRA sent to it
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Fixture is the content of a fixture file
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is a http.RoundTripper that records requests and responses to a fixture file, or replays them from it.
+// Use it with api.Client.SetHTTPClient to run integration tests offline. Credentials are never recorded and email
+// addresses are replaced by ScrubbedEmail
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+}
+
+// New returns a Recorder for the fixture file. In ModeReplay the fixture is read from the file, in ModeRecord
+// requests are sent with http.DefaultTransport and the fixture is written by Save
+func New(path string, mode Mode) (*Recorder, error) {
+	recorder := Recorder{mode: mode, path: path, transport: http.DefaultTransport}
+
+	if mode == ModeRecord {
+		return &recorder, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read fixture %v: %w", path, err)
+	}
+
+	if err = json.Unmarshal(content, &recorder.fixture); err != nil {
+		return nil, fmt.Errorf("Could not read fixture %v: %v", path, err)
+	}
+
+	recorder.used = make([]bool, len(recorder.fixture.Interactions))
+	return &recorder, nil
+}
+
+// ModeFromEnv returns ModeRecord when the environment variable is set to a non empty value, and ModeReplay otherwise
+func ModeFromEnv(name string) Mode {
+	if os.Getenv(name) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// SetTransport sets the transport that sends the requests while recording
+func (r *Recorder) SetTransport(transport http.RoundTripper) {
+	r.transport = transport
+}
+
+// Client returns a http.Client that uses the Recorder as transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.fixture.Interactions...)
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, request)
+	}
+
+	return r.replay(req, request)
+}
+
+// Save writes the recorded interactions to the fixture file, it does nothing in ModeReplay
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, append(content, '\n'), 0644)
+}
+
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	response := Response{StatusCode: resp.StatusCode, Body: scrub(string(body))}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			if response.Header == nil {
+				response.Header = make(map[string]string)
+			}
+			response.Header[name] = value
+		}
+	}
+
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{Request: request, Response: response})
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay returns the response of the first unused interaction that matches the request, so identical requests are
+// answered in the order they were recorded
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.fixture.Interactions {
+		if r.used[i] || interaction.Request != request {
+			continue
+		}
+		r.used[i] = true
+
+		resp := &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}
+		for name, value := range interaction.Response.Header {
+			resp.Header.Set(name, value)
+		}
+		return resp, nil
+	}
+
+	target := request.Path
+	if request.Query != "" {
+		target += "?" + request.Query
+	}
+	return nil, fmt.Errorf("No recorded interaction for %v %v in %v", request.Method, target, r.path)
+}
+
+// newRequest returns the scrubbed recording of the request, the query is sorted so the order of the parameters
+// does not matter
+func newRequest(req *http.Request) (Request, error) {
+	request := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  scrubQuery(req.URL.Query()),
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return request, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	request.Body = scrub(strings.TrimSpace(string(body)))
+	return request, nil
+}
+
+// scrubQuery returns the encoded query with the email addresses in the values scrubbed
+func scrubQuery(query url.Values) string {
+	for _, values := range query {
+		for i := range values {
+			values[i] = scrub(values[i])
+		}
+	}
+	// Encode sorts by key
+	return query.Encode()
+}
+
+func scrub(text string) string {
+	return emailPattern.ReplaceAllString(text, ScrubbedEmail)
+}
@@ -0,0 +1,148 @@
+package recorder_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/recorder"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// record records a search, adding a version to the issue and getting the issue again against a fake JIRA
+func record(t *testing.T, fixture string) {
+	server := fakejira.New()
+	defer server.Close()
+	server.AddProject(1337, "AB")
+	server.AddVersion(jira.Version{Name: "1.0.0", ProjectID: 1337})
+	server.AddIssue(jira.Issue{ID: "1", Key: "AB-1", Fields: &jira.IssueFields{Project: jira.Project{ID: "1337"}}})
+	server.SetMyself(jira.User{Name: "jane", EmailAddress: "jane.doe@company.com"})
+	server.RequireAuth("jane.doe@company.com", "secret-api-token")
+
+	rec, err := recorder.New(fixture, recorder.ModeRecord)
+	assert.Nil(t, err)
+
+	client := testClient(server.URL, rec)
+	_, err = client.Myself()
+	assert.Nil(t, err)
+	issues, err := client.Search(`project = AB AND fixVersion IS EMPTY`)
+	assert.Nil(t, err)
+	assert.Nil(t, client.AddVersionToIssue(issues[0], jira.Version{Name: "1.0.0"}))
+	issue, err := client.GetIssue("AB-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0", issue.Fields.FixVersions[0].Name)
+
+	assert.Nil(t, rec.Save())
+}
+
+func testClient(baseURL string, rec *recorder.Recorder) *api.Client {
+	client, _ := api.NewClient(baseURL, "jane.doe@company.com", "secret-api-token")
+	client.SetHTTPClient(rec.Client())
+	client.SetOutput(ioutil.Discard)
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "recorder")
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "testdata", "release.json")
+
+	record(t, fixture)
+
+	// The server is closed, so every response comes from the fixture
+	rec, err := recorder.New(fixture, recorder.ModeReplay)
+	assert.Nil(t, err)
+	client := testClient("http://jira.example.com", rec)
+
+	user, err := client.Myself()
+	assert.Nil(t, err)
+	assert.Equal(t, recorder.ScrubbedEmail, user.EmailAddress)
+
+	issues, err := client.Search(`project = AB AND fixVersion IS EMPTY`)
+	assert.Nil(t, err)
+	assert.Equal(t, "AB-1", issues[0].Key)
+	assert.Nil(t, client.AddVersionToIssue(issues[0], jira.Version{Name: "1.0.0"}))
+
+	issue, err := client.GetIssue("AB-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0", issue.Fields.FixVersions[0].Name)
+}
+
+func TestFixtureIsSanitized(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "recorder")
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "release.json")
+
+	record(t, fixture)
+
+	content, err := ioutil.ReadFile(fixture)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(content), "jane.doe@company.com"))
+	assert.False(t, strings.Contains(string(content), "secret-api-token"))
+	assert.False(t, strings.Contains(string(content), "Authorization"))
+}
+
+func TestReplayMatchesStrictly(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "recorder")
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "release.json")
+
+	record(t, fixture)
+
+	rec, _ := recorder.New(fixture, recorder.ModeReplay)
+	client := testClient("http://jira.example.com", rec)
+
+	// A different query
+	_, err := client.Search(`project = AB`)
+	assert.Contains(t, err.Error(), "No recorded interaction for GET /rest/api/latest/search?jql=project+%3D+AB&startAt=0")
+
+	// A different body
+	err = client.AddVersionToIssue(jira.Issue{ID: "1", Key: "AB-1"}, jira.Version{Name: "2.0.0"})
+	assert.Contains(t, err.Error(), "No recorded interaction for PUT /rest/api/latest/issue/1")
+
+	// A different method
+	err = client.RemoveVersionFromIssue(jira.Issue{ID: "1", Key: "AB-1"}, jira.Version{Name: "1.0.0"})
+	assert.NotNil(t, err)
+
+	// Every interaction is replayed once
+	_, err = client.Myself()
+	assert.Nil(t, err)
+	_, err = client.Myself()
+	assert.Contains(t, err.Error(), "No recorded interaction for GET /rest/api/latest/myself")
+}
+
+func TestReplayWithoutFixtureReturnsError(t *testing.T) {
+	_, err := recorder.New(filepath.Join("testdata", "missing.json"), recorder.ModeReplay)
+
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestModeFromEnv(t *testing.T) {
+	os.Setenv("RECORDER_TEST_RECORD", "1")
+	defer os.Unsetenv("RECORDER_TEST_RECORD")
+
+	assert.Equal(t, recorder.ModeRecord, recorder.ModeFromEnv("RECORDER_TEST_RECORD"))
+	assert.Equal(t, recorder.ModeReplay, recorder.ModeFromEnv("RECORDER_TEST_UNSET"))
+}
+
+func TestRecordReturnsResponseWithOriginalBody(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+	server.SetMyself(jira.User{Name: "jane", EmailAddress: "jane.doe@company.com"})
+
+	rec, _ := recorder.New(filepath.Join(os.TempDir(), "unused.json"), recorder.ModeRecord)
+	rec.SetTransport(http.DefaultTransport)
+	client := testClient(server.URL, rec)
+
+	user, err := client.Myself()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "jane.doe@company.com", user.EmailAddress)
+	assert.Contains(t, rec.Interactions()[0].Response.Body, `"emailAddress":"`+recorder.ScrubbedEmail+`"`)
+}
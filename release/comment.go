@@ -0,0 +1,108 @@
+package release
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io/ioutil"
+	"strings"
+	"text/template"
+)
+
+// DefaultCommentTemplate is used for release comments when no template is provided
+const DefaultCommentTemplate = `Released in version {{.Version}}{{if .ReleaseDate}} on {{.ReleaseDate}}{{end}}.
+{{- if .Environment}}
+Deployed to {{.Environment}}.{{end}}
+{{- if .BuildURL}}
+Build: {{.BuildURL}}{{end}}
+{{- if .Commits}}
+Commits: {{join (short .Commits) ", "}}{{end}}`
+
+// CommentData contains all data that is passed to a release comment template
+type CommentData struct {
+	Issue       string
+	Version     string
+	ReleaseDate string
+	BuildURL    string
+	Environment string
+	Commits     []string
+}
+
+// CommentTemplate renders release comments for issues
+type CommentTemplate struct {
+	tmpl *template.Template
+}
+
+var commentFuncs = template.FuncMap{
+	"join": strings.Join,
+	"short": func(shas []string) []string {
+		var short []string
+		for _, sha := range shas {
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			short = append(short, sha)
+		}
+		return short
+	},
+}
+
+// NewCommentTemplate returns a CommentTemplate for the provided text/template text, the DefaultCommentTemplate is used
+// when the text is empty
+func NewCommentTemplate(text string) (*CommentTemplate, error) {
+	if text == "" {
+		text = DefaultCommentTemplate
+	}
+
+	tmpl, err := template.New("comment").Funcs(commentFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentTemplate{tmpl: tmpl}, nil
+}
+
+// CommentTemplateFromFile returns a CommentTemplate for the template in the provided file
+func CommentTemplateFromFile(path string) (*CommentTemplate, error) {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCommentTemplate(string(text))
+}
+
+// Render returns the release comment for the provided data
+func (t *CommentTemplate) Render(data CommentData) (*jira.Comment, error) {
+	buffer := new(bytes.Buffer)
+	if err := t.tmpl.Execute(buffer, data); err != nil {
+		return nil, err
+	}
+
+	return jira.NewComment(strings.TrimSpace(buffer.String()))
+}
+
+// HasComment reports if one of the existing comments has the same body as the comment, ignoring surrounding whitespace.
+// Document bodies are compared by their wiki markup
+func HasComment(existing []jira.Comment, comment jira.Comment) bool {
+	body := commentText(comment)
+	if body == "" {
+		return false
+	}
+
+	for _, c := range existing {
+		if commentText(c) == body {
+			return true
+		}
+	}
+
+	return false
+}
+
+func commentText(comment jira.Comment) string {
+	if comment.Document != nil {
+		// Only REST API version 3 returns documents, it mentions users by account ID
+		return strings.TrimSpace(adf.ToWiki(comment.Document, adf.MentionAccountID))
+	}
+	return strings.TrimSpace(comment.Body)
+}
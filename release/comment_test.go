@@ -0,0 +1,72 @@
+package release_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/adf"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultCommentTemplate(t *testing.T) {
+	expected := "Released in version 1.2.0 on 2020-01-16.\n" +
+		"Deployed to production.\n" +
+		"Build: https://ci.example.com/builds/42\n" +
+		"Commits: 9fceb02, 2f8d1d2"
+
+	tmpl, err := release.NewCommentTemplate("")
+	assert.Nil(t, err)
+
+	comment, err := tmpl.Render(release.CommentData{
+		Issue:       "AB-1",
+		Version:     "1.2.0",
+		ReleaseDate: "2020-01-16",
+		BuildURL:    "https://ci.example.com/builds/42",
+		Environment: "production",
+		Commits:     []string{"9fceb02d0ae598e95dc970b74767f19372d61af8", "2f8d1d2"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, comment.Body)
+}
+
+func TestDefaultCommentTemplateWithOnlyVersion(t *testing.T) {
+	tmpl, _ := release.NewCommentTemplate("")
+	comment, err := tmpl.Render(release.CommentData{Issue: "AB-1", Version: "1.2.0"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Released in version 1.2.0.", comment.Body)
+}
+
+func TestCustomCommentTemplate(t *testing.T) {
+	tmpl, err := release.NewCommentTemplate("{{.Issue}} shipped in {{.Version}}")
+	assert.Nil(t, err)
+
+	comment, err := tmpl.Render(release.CommentData{Issue: "AB-1", Version: "1.2.0"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "AB-1 shipped in 1.2.0", comment.Body)
+}
+
+func TestCommentTemplateRendersEmptyComment(t *testing.T) {
+	tmpl, _ := release.NewCommentTemplate("{{if .BuildURL}}{{.BuildURL}}{{end}}")
+	comment, err := tmpl.Render(release.CommentData{Issue: "AB-1", Version: "1.2.0"})
+
+	assert.Equal(t, errors.New("Message cannot be empty"), err)
+	assert.Nil(t, comment)
+}
+
+func TestHasComment(t *testing.T) {
+	existing := []jira.Comment{{Body: "Some discussion"}, {Body: "Released in version 1.2.0.\n"}}
+
+	assert.True(t, release.HasComment(existing, jira.Comment{Body: "Released in version 1.2.0."}))
+	assert.False(t, release.HasComment(existing, jira.Comment{Body: "Released in version 1.3.0."}))
+}
+
+func TestHasCommentComparesDocuments(t *testing.T) {
+	existing := []jira.Comment{{Document: adf.FromMarkdown("Released in version **1.2.0**.")}}
+
+	assert.True(t, release.HasComment(existing, jira.Comment{Body: "Released in version *1.2.0*."}))
+	assert.False(t, release.HasComment(existing, jira.Comment{}))
+}
@@ -0,0 +1,192 @@
+package release_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestRunnerAgainstFakeJira(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.SetTransitions(jira.Transition{ID: "31", Name: "Done", To: &jira.Status{Name: "Done"}})
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Status:  &jira.Status{Name: "Ready for Release"},
+	}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	runner := release.NewRunner(client, server.URL)
+	runner.SetOutput(ioutil.Discard)
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+	assert.Nil(t, runner.Apply(plan))
+
+	issue, _ := server.Issue("AB-1")
+	assert.Equal(t, "Done", issue.Fields.Status.Name)
+	assert.Equal(t, "1.2.0", issue.Fields.FixVersions[0].Name)
+	assert.Equal(t, "Released in 1.2.0", server.Comments("AB-1")[0].Body)
+	assert.True(t, server.Versions(1337)[0].Released)
+
+	// Planning the same release again finds nothing left to do
+	plan, err = runner.Plan(testFile())
+	assert.Nil(t, err)
+	assert.Empty(t, plan.Steps)
+}
+
+// failingCommenter decorates a JiraAPI and fails every comment it creates
+type failingCommenter struct {
+	api.JiraAPI
+	calls int
+}
+
+func (f *failingCommenter) CreateComment(issue jira.Issue, comment jira.Comment) (*jira.Comment, error) {
+	f.calls++
+	return nil, errors.New("Comments are disabled")
+}
+
+func TestRunnerUsesJiraAPI(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Status:  &jira.Status{Name: "Ready for Release"},
+	}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	decorated := &failingCommenter{JiraAPI: client}
+	runner := release.NewRunner(decorated, server.URL)
+	runner.SetOutput(ioutil.Discard)
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+
+	err = runner.Apply(plan)
+
+	assert.Equal(t, "Could not comment AB-1: Comments are disabled", err.Error())
+	assert.Equal(t, 1, decorated.calls)
+	assert.Empty(t, server.Comments("AB-1"))
+}
+
+func TestRunnerWithTwoTransitionsOnOneIssue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.SetTransitions(
+		jira.Transition{ID: "11", Name: "Reopen", To: &jira.Status{Name: "Ready for Release"}},
+		jira.Transition{ID: "21", Name: "Test", To: &jira.Status{Name: "In QA"}},
+		jira.Transition{ID: "31", Name: "Done", To: &jira.Status{Name: "Done"}},
+	)
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Status:  &jira.Status{Name: "Ready for Release"},
+	}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	runner := release.NewRunner(client, server.URL)
+	runner.SetOutput(ioutil.Discard)
+	runJournal, _ := journal.Open(dir, "1337-1.2.0")
+	runner.SetJournal(runJournal)
+
+	file := testFile()
+	file.Comment = nil
+	file.Release = false
+	file.Transitions = []release.TransitionSpec{{To: "In QA"}, {To: "Done"}}
+
+	plan, err := runner.Plan(file)
+	assert.Nil(t, err)
+	assert.Nil(t, runner.Apply(plan))
+
+	issue, _ := server.Issue("AB-1")
+	assert.Equal(t, "Done", issue.Fields.Status.Name)
+
+	var transitions []journal.Entry
+	for _, entry := range runJournal.Entries {
+		if entry.Action == release.ActionTransition {
+			transitions = append(transitions, entry)
+		}
+	}
+	assert.Len(t, transitions, 2)
+	assert.Equal(t, "transition AB-1 In QA", transitions[0].Step)
+	assert.Equal(t, "Ready for Release", transitions[0].FromStatus)
+	assert.Equal(t, "transition AB-1 Done", transitions[1].Step)
+	assert.Equal(t, "In QA", transitions[1].FromStatus)
+
+	// Rolling back undoes the transitions in reverse order, each back to the status before it
+	assert.Nil(t, runner.Rollback(false))
+	issue, _ = server.Issue("AB-1")
+	assert.Equal(t, "Ready for Release", issue.Fields.Status.Name)
+	assert.False(t, runJournal.Done("transition AB-1 In QA"))
+	assert.False(t, runJournal.Done("transition AB-1 Done"))
+}
+
+func TestRunnerReplanAfterRollbackUsesFixedQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	server := fakejira.New()
+	defer server.Close()
+
+	server.AddProject(1337, "AB")
+	server.AddIssue(jira.Issue{Key: "AB-1", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Labels:  []string{"wrong"},
+	}})
+	server.AddIssue(jira.Issue{Key: "AB-2", Fields: &jira.IssueFields{
+		Project: jira.Project{ID: "1337"},
+		Labels:  []string{"right"},
+	}})
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	runner := release.NewRunner(client, server.URL)
+	runner.SetOutput(ioutil.Discard)
+	runJournal, _ := journal.Open(dir, "1337-1.2.0")
+	runner.SetJournal(runJournal)
+
+	file := testFile()
+	file.Comment = nil
+	file.Transitions = nil
+	file.Release = false
+	file.Queries = []release.QuerySpec{{Name: "ready", JQL: "labels = wrong"}}
+
+	plan, err := runner.Plan(file)
+	assert.Nil(t, err)
+	assert.Nil(t, runner.Apply(plan))
+	assert.Nil(t, runner.Rollback(false))
+
+	issue, _ := server.Issue("AB-1")
+	assert.Empty(t, issue.Fields.FixVersions)
+
+	// The fixed query no longer matches AB-1, the rolled back issue is not planned again
+	file.Queries = []release.QuerySpec{{Name: "ready", JQL: "labels = right"}}
+	plan, err = runner.Plan(file)
+	assert.Nil(t, err)
+
+	var steps []string
+	for _, step := range plan.Steps {
+		steps = append(steps, step.String())
+	}
+	assert.Equal(t, []string{"create-version", "assign-version AB-2"}, steps)
+}
@@ -0,0 +1,162 @@
+package release
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// File describes the steps of a release, as read from a release.yaml file
+type File struct {
+	Version VersionSpec `yaml:"version"`
+	// Queries select the issues that are part of the release, issues matched by multiple queries are used once
+	Queries     []QuerySpec      `yaml:"queries"`
+	Comment     *CommentSpec     `yaml:"comment"`
+	Transitions []TransitionSpec `yaml:"transitions"`
+	// Release marks the version as released after all issues are assigned
+	Release bool       `yaml:"release"`
+	Notes   *NotesSpec `yaml:"notes"`
+}
+
+// VersionSpec describes the version that is created and assigned
+type VersionSpec struct {
+	Name        string `yaml:"name"`
+	Project     int    `yaml:"project"`
+	ReleaseDate string `yaml:"release_date"`
+	Description string `yaml:"description"`
+}
+
+// QuerySpec is a named JQL query
+type QuerySpec struct {
+	Name string `yaml:"name"`
+	JQL  string `yaml:"jql"`
+}
+
+// CommentSpec describes the release comment that is posted to every issue, see CommentData for the template fields
+type CommentSpec struct {
+	Template    string `yaml:"template"`
+	File        string `yaml:"file"`
+	Visibility  string `yaml:"visibility"`
+	BuildURL    string `yaml:"build_url"`
+	Environment string `yaml:"environment"`
+}
+
+// TransitionSpec moves issues to a status, To is the name of the transition or of the target status.
+// When Query is set only the issues matched by that query are transitioned
+type TransitionSpec struct {
+	Query string `yaml:"query"`
+	To    string `yaml:"to"`
+}
+
+// NotesSpec describes the release notes that are written after the release, see the notes package
+type NotesSpec struct {
+	Format   string `yaml:"format"`
+	GroupBy  string `yaml:"group_by"`
+	Template string `yaml:"template"`
+	Output   string `yaml:"output"`
+	// JQL selects the issues in the notes, defaults to all issues in the version
+	JQL string `yaml:"jql"`
+}
+
+// Lookup returns the value of a variable and whether it is set
+type Lookup func(name string) (string, bool)
+
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// VariableLookup returns a Lookup that uses the provided variables first and falls back to the env lookup
+func VariableLookup(vars map[string]string, lookupEnv Lookup) Lookup {
+	return func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		if lookupEnv == nil {
+			return "", false
+		}
+		return lookupEnv(name)
+	}
+}
+
+// ParseFile substitutes ${NAME} variables in the content and returns the release file it describes.
+// An error is returned when a variable is not set
+func ParseFile(content []byte, lookup Lookup) (*File, error) {
+	var missing []string
+	seen := make(map[string]bool)
+	expanded := variablePattern.ReplaceAllStringFunc(string(content), func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if lookup != nil {
+			if value, ok := lookup(name); ok {
+				return value
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+		return match
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Variables are not set: %v", strings.Join(missing, ", "))
+	}
+
+	var file File
+	if err := yaml.UnmarshalStrict([]byte(expanded), &file); err != nil {
+		return nil, err
+	}
+
+	if err := file.validate(); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// LoadFile reads and parses the release file at path, see ParseFile
+func LoadFile(path string, lookup Lookup) (*File, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ParseFile(content, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read release file %v: %v", path, err)
+	}
+
+	return file, nil
+}
+
+func (f *File) validate() error {
+	if f.Version.Name == "" {
+		return fmt.Errorf("Version name cannot be empty")
+	}
+
+	if len(f.Queries) == 0 {
+		return fmt.Errorf("At least one query is required")
+	}
+
+	names := make(map[string]bool)
+	for _, query := range f.Queries {
+		if query.JQL == "" {
+			return fmt.Errorf("Query %v has no jql", query.Name)
+		}
+		names[query.Name] = true
+	}
+
+	for _, transition := range f.Transitions {
+		if transition.To == "" {
+			return fmt.Errorf("Transition must have a target")
+		}
+		if transition.Query != "" && !names[transition.Query] {
+			return fmt.Errorf("Transition to %v refers to unknown query %v", transition.To, transition.Query)
+		}
+	}
+
+	if f.Comment != nil && f.Comment.Template != "" && f.Comment.File != "" {
+		return fmt.Errorf("Comment template and file cannot both be set")
+	}
+
+	return nil
+}
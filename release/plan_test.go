@@ -0,0 +1,82 @@
+package release_test
+
+import (
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testReleaseFile = `version:
+  name: ${VERSION}
+  project: ${PROJECT}
+  release_date: "2020-06-01"
+queries:
+  - name: ready
+    jql: project = ${PROJECT} AND status = "Ready for Release"
+comment:
+  template: Released in {{.Version}}
+  visibility: role:Developers
+transitions:
+  - query: ready
+    to: Done
+release: true
+notes:
+  format: markdown
+  output: NOTES.md
+`
+
+func TestParseFile(t *testing.T) {
+	lookup := release.VariableLookup(map[string]string{"VERSION": "1.2.0"}, func(name string) (string, bool) {
+		if name == "PROJECT" {
+			return "1337", true
+		}
+		return "", false
+	})
+
+	file, err := release.ParseFile([]byte(testReleaseFile), lookup)
+
+	assert.Nil(t, err)
+	assert.Equal(t, release.VersionSpec{Name: "1.2.0", Project: 1337, ReleaseDate: "2020-06-01"}, file.Version)
+	assert.Equal(t, []release.QuerySpec{{Name: "ready", JQL: "project = 1337 AND status = \"Ready for Release\""}}, file.Queries)
+	assert.Equal(t, "Released in {{.Version}}", file.Comment.Template)
+	assert.Equal(t, []release.TransitionSpec{{Query: "ready", To: "Done"}}, file.Transitions)
+	assert.True(t, file.Release)
+	assert.Equal(t, "NOTES.md", file.Notes.Output)
+}
+
+func TestParseFileVariablesFromFlagsWin(t *testing.T) {
+	env := func(name string) (string, bool) { return "from-env", true }
+	lookup := release.VariableLookup(map[string]string{"VERSION": "from-flag"}, env)
+
+	value, ok := lookup("VERSION")
+
+	assert.True(t, ok)
+	assert.Equal(t, "from-flag", value)
+}
+
+func TestParseFileMissingVariablesReturnsError(t *testing.T) {
+	_, err := release.ParseFile([]byte(testReleaseFile), release.VariableLookup(nil, nil))
+
+	assert.EqualError(t, err, "Variables are not set: VERSION, PROJECT")
+}
+
+func TestParseFileUnknownQueryReturnsError(t *testing.T) {
+	content := `version:
+  name: 1.2.0
+queries:
+  - name: ready
+    jql: status = Done
+transitions:
+  - query: other
+    to: Done
+`
+	_, err := release.ParseFile([]byte(content), nil)
+
+	assert.EqualError(t, err, "Transition to Done refers to unknown query other")
+}
+
+func TestParseFileUnknownFieldReturnsError(t *testing.T) {
+	_, err := release.ParseFile([]byte("version:\n  name: 1.2.0\n  colour: blue\n"), nil)
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/journal"
+)
+
+// Rollback undoes the completed steps in the journal of the runner, most recent first: comments are deleted,
+// transitions are reverted when the workflow allows it, the fixVersion is removed and the version is deleted when
+// the run created it. Steps that cannot be undone, like releasing the version, are reported and left in the journal
+func (r *Runner) Rollback(dryRun bool) error {
+	if r.journal == nil {
+		return fmt.Errorf("A journal is required to roll back a run")
+	}
+
+	version := jira.Version{Name: r.journal.Version}
+
+	for _, entry := range r.journal.Completed() {
+		issue := jira.Issue{ID: entry.IssueID, Key: entry.Issue}
+		if issue.ID == "" {
+			// JIRA accepts issue keys wherever an ID is expected
+			issue.ID = entry.Issue
+		}
+
+		description, undo := r.undo(entry, issue, version)
+		if undo == nil {
+			fmt.Fprintf(r.out, "Cannot roll back %v: %v\n", entry.Step, description)
+			continue
+		}
+
+		if dryRun {
+			fmt.Fprintf(r.out, "Dry run: would %v\n", description)
+			continue
+		}
+
+		if err := undo(); err != nil {
+			return fmt.Errorf("Could not %v: %w", description, err)
+		}
+
+		// An issue without the version is no longer part of the run, so a re-run with a fixed query does not
+		// assign the version to it again
+		if entry.Action == ActionAssignVersion {
+			r.journal.RemoveIssue(entry.Issue)
+		}
+
+		if err := r.journal.MarkRolledBack(entry.Step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// undo returns a description of how the entry is rolled back and the function that does it. The function is nil
+// when the entry cannot be rolled back, the description then explains why
+func (r *Runner) undo(entry journal.Entry, issue jira.Issue, version jira.Version) (string, func() error) {
+	switch entry.Action {
+	case ActionCreateVersion:
+		if entry.VersionID == "" {
+			return "the version ID was not recorded", nil
+		}
+		version.ID = entry.VersionID
+		return fmt.Sprintf("delete version %v", version.Name), func() error {
+			return r.client.DeleteVersion(version)
+		}
+	case ActionAssignVersion:
+		return fmt.Sprintf("remove version %v from %v", version.Name, issue.Key), func() error {
+			return r.client.RemoveVersionFromIssue(issue, version)
+		}
+	case ActionComment:
+		if entry.CommentID == "" {
+			return "the comment ID was not recorded", nil
+		}
+		return fmt.Sprintf("delete release comment %v on %v", entry.CommentID, issue.Key), func() error {
+			if err := r.client.DeleteComment(issue, entry.CommentID); err != nil {
+				return err
+			}
+			fmt.Fprintf(r.out, "Successfully deleted release comment on issue %v\n", issue.Key)
+			return nil
+		}
+	case ActionTransition:
+		if entry.FromStatus == "" {
+			return "the previous status was not recorded", nil
+		}
+		return fmt.Sprintf("transition %v back to %v", issue.Key, entry.FromStatus), func() error {
+			_, err := r.transition(issue, entry.FromStatus)
+			return err
+		}
+	case ActionReleaseVersion:
+		return "releasing a version is not undone", nil
+	case ActionWriteNotes:
+		return "release notes are files and are not removed", nil
+	}
+
+	return fmt.Sprintf("unknown action %v", entry.Action), nil
+}
@@ -0,0 +1,99 @@
+package release_test
+
+import (
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func testJournal(t *testing.T, dir string) *journal.Journal {
+	j, err := journal.Open(dir, "1337-1.2.0")
+	assert.Nil(t, err)
+	j.Version = "1.2.0"
+
+	assert.Nil(t, j.Begin())
+	entries := []journal.Entry{
+		{Step: "create-version", Action: "create-version", VersionID: "20"},
+		{Step: "assign-version AB-1", Action: "assign-version", Issue: "AB-1", IssueID: "1"},
+		{Step: "comment AB-1", Action: "comment", Issue: "AB-1", IssueID: "1", CommentID: "100"},
+		{Step: "transition AB-1", Action: "transition", Issue: "AB-1", IssueID: "1", FromStatus: "Ready for Release"},
+		{Step: "release-version", Action: "release-version"},
+	}
+	for _, entry := range entries {
+		assert.Nil(t, j.Record(entry))
+	}
+	assert.Nil(t, j.End(nil))
+
+	return j
+}
+
+func TestRunnerRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var requests []string
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+
+		switch req.Method + " " + req.URL.Path {
+		case "GET /rest/api/latest/issue/1/transitions":
+			writer.Write([]byte(`{"transitions":[{"id":"11","name":"Reopen","to":{"name":"Ready for Release"}}]}`))
+		case "PUT /rest/api/latest/issue/1":
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"update":{"fixVersions":[{"remove":{"name":"1.2.0"}}]}}`, string(body))
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writer.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	j := testJournal(t, dir)
+	runner.SetJournal(j)
+
+	assert.Nil(t, runner.Rollback(false))
+	assert.Equal(t, []string{
+		"GET /rest/api/latest/issue/1/transitions",
+		"POST /rest/api/latest/issue/1/transitions",
+		"DELETE /rest/api/latest/issue/1/comment/100",
+		"PUT /rest/api/latest/issue/1",
+		"DELETE /rest/api/latest/version/20",
+	}, requests)
+
+	// Releasing the version cannot be undone, so it stays in the journal
+	reopened, _ := journal.Open(dir, "1337-1.2.0")
+	assert.Len(t, reopened.Completed(), 1)
+	assert.False(t, reopened.Done("assign-version AB-1"))
+}
+
+func TestRunnerRollbackDryRunMakesNoRequests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		t.Errorf("Unexpected request %v %v", req.Method, req.URL.Path)
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	j := testJournal(t, dir)
+	runner.SetJournal(j)
+
+	assert.Nil(t, runner.Rollback(true))
+	assert.Len(t, j.Completed(), 5)
+}
+
+func TestRunnerRollbackWithoutJournalReturnsError(t *testing.T) {
+	runner, closeServer := testRunner(http.NotFoundHandler())
+	defer closeServer()
+
+	assert.EqualError(t, runner.Rollback(false), "A journal is required to roll back a run")
+}
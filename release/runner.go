@@ -0,0 +1,683 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/marcelblijleven/version-meister/notes"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+)
+
+// Actions of the steps in a release plan
+const (
+	ActionCreateVersion  = "create-version"
+	ActionAssignVersion  = "assign-version"
+	ActionComment        = "comment"
+	ActionTransition     = "transition"
+	ActionReleaseVersion = "release-version"
+	ActionWriteNotes     = "write-notes"
+)
+
+// versionJQLTemplate selects all issues in a version, it is used for release notes without a query
+const versionJQLTemplate = "project = %v AND fixVersion = %v"
+
+// Step is a single change that applying a plan makes
+type Step struct {
+	Action string
+	// Issue is set for steps that change an issue
+	Issue *jira.Issue
+	// Comment is set for comment steps
+	Comment *jira.Comment
+	// Transition is the target of transition steps
+	Transition string
+}
+
+// Plan lists the steps that are needed to bring JIRA in line with a release file.
+// Steps that are already done, like a version that exists or an issue that has the release comment, are left out
+type Plan struct {
+	File    *File
+	Version jira.Version
+	Issues  []jira.Issue
+	Steps   []Step
+	// matches holds the names of the queries that matched each issue
+	matches map[string][]string
+}
+
+// Runner creates and applies release plans
+type Runner struct {
+	client      api.JiraAPI
+	baseURL     string
+	journal     *journal.Journal
+	out         io.Writer
+	concurrency int
+	// mu guards the journal when issues are processed concurrently, copies of the runner share it
+	mu *sync.Mutex
+	// ctx is the context of the client requests, see withContext
+	ctx            context.Context
+	tracerProvider trace.TracerProvider
+}
+
+// NewRunner returns a Runner that uses the client, the base url is used for links in release notes
+func NewRunner(client api.JiraAPI, baseURL string) *Runner {
+	return &Runner{client: client, baseURL: baseURL, out: os.Stdout, concurrency: 1, mu: new(sync.Mutex)}
+}
+
+// SetConcurrency sets the number of issues whose steps are applied at the same time, 1 by default. The steps of
+// an issue are always applied in order
+func (r *Runner) SetConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	r.concurrency = concurrency
+}
+
+// SetOutput sets the writer that progress messages and release notes without an output file are written to
+func (r *Runner) SetOutput(out io.Writer) {
+	r.out = out
+}
+
+// SetJournal makes the runner resume the run in the journal: steps that an earlier attempt completed are left out
+// of the plan, issues of earlier attempts stay part of the run and Apply records every completed step
+func (r *Runner) SetJournal(j *journal.Journal) {
+	r.journal = j
+}
+
+// Plan returns the steps for the release file. Planning only reads from JIRA
+func (r *Runner) Plan(file *File) (*Plan, error) {
+	return r.PlanContext(context.Background(), file)
+}
+
+// PlanContext is Plan with a context, planning is traced in a span that is a child of the span in the context
+func (r *Runner) PlanContext(ctx context.Context, file *File) (_ *Plan, err error) {
+	runner, span := r.withContext(ctx).startSpan("release.plan", api.AttributeVersion.String(file.Version.Name))
+	defer func() { endSpan(span, err) }()
+
+	return runner.plan(file)
+}
+
+func (r *Runner) plan(file *File) (*Plan, error) {
+	version, err := jira.NewVersion(file.Version.Name, false, file.Version.ReleaseDate, file.Version.Project)
+	if err != nil {
+		return nil, err
+	}
+	version.Description = file.Version.Description
+
+	plan := Plan{File: file, Version: *version}
+
+	existing, err := r.client.FindVersion(version.ProjectID, version.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		plan.Steps = append(plan.Steps, Step{Action: ActionCreateVersion})
+	} else {
+		plan.Version.ID = existing.ID
+		plan.Version.Released = existing.Released
+	}
+
+	matches, err := r.search(&plan)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.matches = make(map[string][]string)
+	for key, queries := range matches {
+		for query := range queries {
+			plan.matches[key] = append(plan.matches[key], query)
+		}
+		sort.Strings(plan.matches[key])
+	}
+
+	var commentTemplate *CommentTemplate
+	var visibility *jira.Visibility
+	if file.Comment != nil {
+		if commentTemplate, visibility, err = newComment(*file.Comment); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range plan.Issues {
+		issue := &plan.Issues[i]
+
+		if !hasFixVersion(*issue, version.Name) {
+			plan.Steps = append(plan.Steps, Step{Action: ActionAssignVersion, Issue: issue})
+		}
+
+		if commentTemplate != nil {
+			step, err := r.commentStep(issue, plan.Version, *file.Comment, commentTemplate, visibility)
+			if err != nil {
+				return nil, err
+			}
+			if step != nil {
+				plan.Steps = append(plan.Steps, *step)
+			}
+		}
+
+		for _, transition := range file.Transitions {
+			if transition.Query != "" && !matches[issue.Key][transition.Query] {
+				continue
+			}
+			if issueStatus(*issue) != "" && strings.EqualFold(issueStatus(*issue), transition.To) {
+				continue
+			}
+			plan.Steps = append(plan.Steps, Step{Action: ActionTransition, Issue: issue, Transition: transition.To})
+		}
+	}
+
+	if file.Release && !plan.Version.Released {
+		plan.Steps = append(plan.Steps, Step{Action: ActionReleaseVersion})
+	}
+
+	if file.Notes != nil {
+		plan.Steps = append(plan.Steps, Step{Action: ActionWriteNotes})
+	}
+
+	if r.journal != nil {
+		plan.Steps = r.pending(plan.Steps)
+	}
+
+	return &plan, nil
+}
+
+// pending returns the steps that the journal has not recorded as completed
+func (r *Runner) pending(steps []Step) []Step {
+	var pending []Step
+	for _, step := range steps {
+		if !r.journal.Done(step.String()) {
+			pending = append(pending, step)
+		}
+	}
+	return pending
+}
+
+// search runs the queries of the plan and returns the names of the queries that matched each issue
+func (r *Runner) search(plan *Plan) (map[string]map[string]bool, error) {
+	matches := make(map[string]map[string]bool)
+
+	for _, query := range plan.File.Queries {
+		runner, span := r.startSpan("release.search", attribute.String("release.query", query.Name), api.AttributeJQL.String(query.JQL))
+		issues, err := runner.client.Search(query.JQL)
+		endSpan(span, err)
+		if err != nil {
+			return nil, fmt.Errorf("Query %v failed: %w", query.Name, err)
+		}
+
+		for _, issue := range issues {
+			if _, ok := matches[issue.Key]; !ok {
+				matches[issue.Key] = make(map[string]bool)
+				plan.Issues = append(plan.Issues, issue)
+			}
+			matches[issue.Key][query.Name] = true
+		}
+	}
+
+	if r.journal == nil {
+		return matches, nil
+	}
+
+	var keys []string
+	for key := range r.journal.Issues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Issues from earlier attempts may no longer match their query, e.g. because their fixVersion is set now
+	for _, key := range keys {
+		if _, ok := matches[key]; ok {
+			continue
+		}
+
+		issue, err := r.client.GetIssue(key)
+		if err == api.ErrIssueNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		matches[key] = make(map[string]bool)
+		for _, query := range r.journal.Issues[key] {
+			matches[key][query] = true
+		}
+		plan.Issues = append(plan.Issues, *issue)
+	}
+
+	return matches, nil
+}
+
+// commentStep returns the comment step for the issue, or nil when the issue already has the release comment
+func (r *Runner) commentStep(issue *jira.Issue, version jira.Version, spec CommentSpec,
+	commentTemplate *CommentTemplate, visibility *jira.Visibility) (*Step, error) {
+	comment, err := commentTemplate.Render(CommentData{
+		Issue:       issue.Key,
+		Version:     version.Name,
+		ReleaseDate: version.ReleaseDate,
+		BuildURL:    spec.BuildURL,
+		Environment: spec.Environment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	comment.Visibility = visibility
+
+	existing, err := r.client.ListComments(*issue)
+	if err != nil {
+		return nil, err
+	}
+
+	if HasComment(existing, *comment) {
+		return nil, nil
+	}
+
+	return &Step{Action: ActionComment, Issue: issue, Comment: comment}, nil
+}
+
+// Apply executes the steps of the plan in order and stops at the first step that fails. With a concurrency above 1
+// the steps of several issues are applied at the same time, see SetConcurrency
+func (r *Runner) Apply(plan *Plan) error {
+	return r.ApplyContext(context.Background(), plan)
+}
+
+// ApplyContext is Apply with a context, every step is traced in a span that is a child of the span in the context
+func (r *Runner) ApplyContext(ctx context.Context, plan *Plan) (err error) {
+	runner, span := r.withContext(ctx).startSpan("release.apply",
+		api.AttributeVersion.String(plan.Version.Name), attribute.Int("release.steps", len(plan.Steps)))
+	defer func() { endSpan(span, err) }()
+
+	return runner.applyPlan(plan)
+}
+
+func (r *Runner) applyPlan(plan *Plan) error {
+	if r.journal == nil {
+		return r.apply(plan)
+	}
+
+	r.journal.Version = plan.Version.Name
+	for key, queries := range plan.matches {
+		r.journal.AddIssue(key, queries)
+	}
+
+	if err := r.journal.Begin(); err != nil {
+		return err
+	}
+
+	err := r.apply(plan)
+	if endErr := r.journal.End(err); endErr != nil && err == nil {
+		return endErr
+	}
+
+	return err
+}
+
+// apply applies the steps before the first issue step and after the last one in order, and the steps of the
+// issues with the configured concurrency
+func (r *Runner) apply(plan *Plan) error {
+	first, last := len(plan.Steps), len(plan.Steps)
+	for i, step := range plan.Steps {
+		if step.Issue == nil {
+			continue
+		}
+		if first == len(plan.Steps) {
+			first = i
+		}
+		last = i + 1
+	}
+
+	if err := r.applySteps(plan, plan.Steps[:first]); err != nil {
+		return err
+	}
+	if err := r.applyIssues(plan, plan.Steps[first:last]); err != nil {
+		return err
+	}
+	return r.applySteps(plan, plan.Steps[last:])
+}
+
+// applyIssues groups the steps by issue and applies the groups concurrently. After a failure no new issues are
+// started, the error of the first failed issue in the plan is returned
+func (r *Runner) applyIssues(plan *Plan, steps []Step) error {
+	if r.concurrency <= 1 {
+		return r.applySteps(plan, steps)
+	}
+
+	var groups [][]Step
+	for _, step := range steps {
+		if len(groups) > 0 && groups[len(groups)-1][0].Issue.Key == step.Issue.Key {
+			groups[len(groups)-1] = append(groups[len(groups)-1], step)
+			continue
+		}
+		groups = append(groups, []Step{step})
+	}
+
+	errs := make([]error, len(groups))
+	var failed int32
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, r.concurrency)
+
+	for i, group := range groups {
+		semaphore <- struct{}{}
+		if atomic.LoadInt32(&failed) != 0 {
+			<-semaphore
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, group []Step) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			if errs[i] = r.applySteps(plan, group); errs[i] != nil {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i, group)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySteps applies the steps in order, each in its own span, and stops at the first step that fails
+func (r *Runner) applySteps(plan *Plan, steps []Step) error {
+	for _, step := range steps {
+		if r.done(step) {
+			continue
+		}
+
+		runner, span := r.startSpan("release."+step.Action, stepAttributes(plan, step)...)
+		entry, err := runner.applyStep(plan, step)
+		endSpan(span, err)
+		if err != nil {
+			return fmt.Errorf("Could not %v: %w", step, err)
+		}
+
+		if err = r.record(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) done(step Step) bool {
+	if r.journal == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.journal.Done(step.String())
+}
+
+// record saves the entry in the journal, when the runner has one
+func (r *Runner) record(entry journal.Entry) error {
+	if r.journal == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.journal.Record(entry)
+}
+
+// applyStep executes the step and returns the journal entry for it, with the details needed to roll it back
+func (r *Runner) applyStep(plan *Plan, step Step) (journal.Entry, error) {
+	entry := journal.Entry{Step: step.String(), Action: step.Action}
+	if step.Issue != nil {
+		entry.Issue = step.Issue.Key
+		entry.IssueID = step.Issue.ID
+	}
+
+	var err error
+	switch step.Action {
+	case ActionCreateVersion:
+		if err = r.client.CreateVersion(plan.Version); err == nil {
+			err = r.resolveVersionID(plan)
+			entry.VersionID = plan.Version.ID
+		}
+	case ActionAssignVersion:
+		err = r.client.AddVersionToIssue(*step.Issue, plan.Version)
+	case ActionComment:
+		var comment *jira.Comment
+		if comment, err = r.client.CreateComment(*step.Issue, *step.Comment); err == nil {
+			entry.CommentID = comment.ID
+			fmt.Fprintf(r.out, "Successfully added release comment to issue %v\n", step.Issue.Key)
+		}
+	case ActionTransition:
+		// The status before this transition, an earlier transition of the run may have changed it since planning
+		entry.FromStatus = issueStatus(*step.Issue)
+		var status string
+		if status, err = r.transition(*step.Issue, step.Transition); err == nil {
+			setIssueStatus(step.Issue, status)
+		}
+	case ActionReleaseVersion:
+		if plan.Version.ID == "" {
+			err = r.resolveVersionID(plan)
+		}
+		if err == nil {
+			err = r.client.ReleaseVersion(plan.Version)
+		}
+	case ActionWriteNotes:
+		err = r.writeNotes(plan)
+	default:
+		err = fmt.Errorf("Unknown action %v", step.Action)
+	}
+
+	return entry, err
+}
+
+func (r *Runner) resolveVersionID(plan *Plan) error {
+	version, err := r.client.FindVersion(plan.Version.ProjectID, plan.Version.Name)
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		return fmt.Errorf("Version %v does not exist", plan.Version.Name)
+	}
+
+	plan.Version.ID = version.ID
+	return nil
+}
+
+// transition performs the transition to the status or with the name, and returns the status the issue is in after it
+func (r *Runner) transition(issue jira.Issue, to string) (string, error) {
+	transitions, err := r.client.GetTransitions(issue)
+	if err != nil {
+		return "", err
+	}
+
+	for _, transition := range transitions {
+		if transition.Matches(to) {
+			if transition.To != nil {
+				to = transition.To.Name
+			}
+			return to, r.client.TransitionIssue(issue, transition.ID)
+		}
+	}
+
+	return "", fmt.Errorf("No transition to %v is available for issue %v", to, issue.Key)
+}
+
+func (r *Runner) writeNotes(plan *Plan) error {
+	spec := plan.File.Notes
+
+	jql := spec.JQL
+	if jql == "" {
+		jql = fmt.Sprintf(versionJQLTemplate, plan.Version.ProjectID, jira.QuoteJQL(plan.Version.Name))
+	}
+
+	issues, err := r.client.Search(jql)
+	if err != nil {
+		return err
+	}
+
+	groupBy := spec.GroupBy
+	if groupBy == "" {
+		groupBy = "type"
+	}
+
+	releaseNotes, err := notes.NewReleaseNotes(plan.Version.Name, plan.Version.ReleaseDate, r.baseURL, groupBy, issues)
+	if err != nil {
+		return err
+	}
+
+	var tmpl *template.Template
+	if spec.Template != "" {
+		tmpl, err = notes.TemplateFromFile(spec.Template)
+	} else if spec.Format != "" {
+		tmpl, err = notes.Template(spec.Format)
+	} else {
+		tmpl, err = notes.Template(notes.FormatMarkdown)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	writer := r.out
+	if spec.Output != "" {
+		file, err := os.Create(spec.Output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	return notes.Render(writer, tmpl, releaseNotes)
+}
+
+// Write writes a human readable version of the plan to the writer
+func (p *Plan) Write(writer io.Writer) error {
+	if len(p.Steps) == 0 {
+		_, err := fmt.Fprintf(writer, "Version %v is up to date, no changes\n", p.Version.Name)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(writer, "Plan for version %v: %v issue(s), %v step(s)\n\n",
+		p.Version.Name, len(p.Issues), len(p.Steps)); err != nil {
+		return err
+	}
+
+	for _, step := range p.Steps {
+		if _, err := fmt.Fprintf(writer, "  %v\n", step.describe(p)); err != nil {
+			return err
+		}
+
+		if step.Comment == nil {
+			continue
+		}
+
+		for _, line := range strings.Split(commentText(*step.Comment), "\n") {
+			if _, err := fmt.Fprintf(writer, "      | %v\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a short description of the step
+func (s Step) String() string {
+	switch s.Action {
+	case ActionAssignVersion, ActionComment:
+		return fmt.Sprintf("%v %v", s.Action, s.Issue.Key)
+	case ActionTransition:
+		// An issue can have several transitions in a run, the target keeps their journal entries apart
+		return fmt.Sprintf("%v %v %v", s.Action, s.Issue.Key, s.Transition)
+	}
+	return s.Action
+}
+
+func (s Step) describe(plan *Plan) string {
+	switch s.Action {
+	case ActionCreateVersion:
+		return fmt.Sprintf("+ create version %v in project %v", plan.Version.Name, plan.Version.ProjectID)
+	case ActionAssignVersion:
+		return fmt.Sprintf("~ assign version %v to %v", plan.Version.Name, s.Issue.Key)
+	case ActionComment:
+		return fmt.Sprintf("+ comment on %v", s.Issue.Key)
+	case ActionTransition:
+		return fmt.Sprintf("~ transition %v to %v", s.Issue.Key, s.Transition)
+	case ActionReleaseVersion:
+		return fmt.Sprintf("~ release version %v", plan.Version.Name)
+	case ActionWriteNotes:
+		output := plan.File.Notes.Output
+		if output == "" {
+			output = "stdout"
+		}
+		return fmt.Sprintf("+ write release notes to %v", output)
+	}
+	return s.Action
+}
+
+func newComment(spec CommentSpec) (*CommentTemplate, *jira.Visibility, error) {
+	var commentTemplate *CommentTemplate
+	var err error
+	if spec.File != "" {
+		commentTemplate, err = CommentTemplateFromFile(spec.File)
+	} else {
+		commentTemplate, err = NewCommentTemplate(spec.Template)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if spec.Visibility == "" {
+		return commentTemplate, nil, nil
+	}
+
+	visibility, err := jira.ParseVisibility(spec.Visibility)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return commentTemplate, visibility, nil
+}
+
+func hasFixVersion(issue jira.Issue, name string) bool {
+	if issue.Fields == nil {
+		return false
+	}
+
+	for _, version := range issue.Fields.FixVersions {
+		if version.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setIssueStatus updates the status of the planned issue after a transition, so later steps see the current status
+func setIssueStatus(issue *jira.Issue, status string) {
+	if issue.Fields == nil {
+		issue.Fields = &jira.IssueFields{}
+	}
+	issue.Fields.Status = &jira.Status{Name: status}
+}
+
+func issueStatus(issue jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return ""
+	}
+	return issue.Fields.Status.Name
+}
@@ -0,0 +1,238 @@
+package release_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/journal"
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// testRunner returns a runner whose client talks to the handler
+func testRunner(handler http.Handler) (*release.Runner, func()) {
+	server := httptest.NewServer(handler)
+
+	client, _ := api.NewClient("http://fake.com", "username", "password")
+	client.SetHTTPClient(&http.Client{
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, network, _ string) (net.Conn, error) {
+				return net.Dial(network, server.Listener.Addr().String())
+			},
+		},
+	})
+
+	return release.NewRunner(client, "http://fake.com"), server.Close
+}
+
+const searchResponse = `{"startAt":0,"maxResults":50,"total":2,"issues":[
+{"id":"1","key":"AB-1","fields":{"project":{"id":"1337"},"fixVersions":[],"status":{"name":"Ready for Release"}}},
+{"id":"2","key":"AB-2","fields":{"project":{"id":"1337"},"fixVersions":[{"name":"1.2.0"}],"status":{"name":"Done"}}}]}`
+
+func testFile() *release.File {
+	return &release.File{
+		Version:     release.VersionSpec{Name: "1.2.0", Project: 1337, ReleaseDate: "2020-06-01"},
+		Queries:     []release.QuerySpec{{Name: "ready", JQL: "status = \"Ready for Release\""}},
+		Comment:     &release.CommentSpec{Template: "Released in {{.Version}}"},
+		Transitions: []release.TransitionSpec{{To: "Done"}},
+		Release:     true,
+	}
+}
+
+func TestRunnerPlan(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/latest/project/1337/versions":
+			writer.Write([]byte(`[]`))
+		case "/rest/api/latest/search":
+			writer.Write([]byte(searchResponse))
+		case "/rest/api/latest/issue/1/comment":
+			writer.Write([]byte(`{"startAt":0,"maxResults":50,"total":0,"comments":[]}`))
+		case "/rest/api/latest/issue/2/comment":
+			writer.Write([]byte(`{"startAt":0,"maxResults":50,"total":1,"comments":[{"id":"10","body":"Released in 1.2.0"}]}`))
+		default:
+			t.Errorf("Unexpected request %v %v", req.Method, req.URL.Path)
+		}
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+
+	var actions []string
+	for _, step := range plan.Steps {
+		actions = append(actions, step.String())
+	}
+
+	expected := []string{
+		"create-version",
+		"assign-version AB-1",
+		"comment AB-1",
+		"transition AB-1 Done",
+		"release-version",
+	}
+	assert.Equal(t, expected, actions)
+
+	output := new(bytes.Buffer)
+	assert.Nil(t, plan.Write(output))
+	assert.Contains(t, output.String(), "Plan for version 1.2.0: 2 issue(s), 5 step(s)")
+	assert.Contains(t, output.String(), "~ transition AB-1 to Done")
+	assert.Contains(t, output.String(), "      | Released in 1.2.0")
+}
+
+func TestRunnerApply(t *testing.T) {
+	var requests []string
+	created := false
+
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+
+		switch req.Method + " " + req.URL.Path {
+		case "GET /rest/api/latest/project/1337/versions":
+			if created {
+				writer.Write([]byte(`[{"id":"20","name":"1.2.0"}]`))
+				return
+			}
+			writer.Write([]byte(`[]`))
+		case "GET /rest/api/latest/search":
+			writer.Write([]byte(searchResponse))
+		case "GET /rest/api/latest/issue/1/comment", "GET /rest/api/latest/issue/2/comment":
+			writer.Write([]byte(`{"startAt":0,"maxResults":50,"total":0,"comments":[]}`))
+		case "POST /rest/api/latest/version":
+			created = true
+			writer.WriteHeader(http.StatusCreated)
+		case "PUT /rest/api/latest/issue/1", "POST /rest/api/latest/issue/1/transitions":
+			writer.WriteHeader(http.StatusNoContent)
+		case "POST /rest/api/latest/issue/1/comment", "POST /rest/api/latest/issue/2/comment":
+			writer.WriteHeader(http.StatusCreated)
+			writer.Write([]byte(`{"id":"100","body":"Released in 1.2.0"}`))
+		case "GET /rest/api/latest/issue/1/transitions":
+			writer.Write([]byte(`{"transitions":[{"id":"31","name":"Release","to":{"name":"Done"}}]}`))
+		case "PUT /rest/api/latest/version/20":
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.JSONEq(t, `{"released":true,"releaseDate":"2020-06-01"}`, string(body))
+		default:
+			t.Errorf("Unexpected request %v %v", req.Method, req.URL.Path)
+		}
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+
+	requests = nil
+	err = runner.Apply(plan)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"POST /rest/api/latest/version",
+		"GET /rest/api/latest/project/1337/versions",
+		"PUT /rest/api/latest/issue/1",
+		"POST /rest/api/latest/issue/1/comment",
+		"GET /rest/api/latest/issue/1/transitions",
+		"POST /rest/api/latest/issue/1/transitions",
+		"POST /rest/api/latest/issue/2/comment",
+		"PUT /rest/api/latest/version/20",
+	}, requests)
+}
+
+func TestRunnerApplyMissingTransitionReturnsError(t *testing.T) {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/latest/project/1337/versions":
+			writer.Write([]byte(`[{"id":"20","name":"1.2.0"}]`))
+		case "/rest/api/latest/search":
+			writer.Write([]byte(searchResponse))
+		case "/rest/api/latest/issue/1/transitions":
+			writer.Write([]byte(`{"transitions":[]}`))
+		}
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	file := testFile()
+	file.Comment = nil
+	file.Release = false
+	plan, err := runner.Plan(file)
+	assert.Nil(t, err)
+
+	// Skip assigning the version, only the transition is applied
+	plan.Steps = plan.Steps[1:]
+	err = runner.Apply(plan)
+
+	assert.EqualError(t, err, "Could not transition AB-1 Done: No transition to Done is available for issue AB-1")
+}
+
+func TestRunnerApplyResumesFromJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var requests []string
+	failComment := true
+
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+
+		switch req.Method + " " + req.URL.Path {
+		case "GET /rest/api/latest/project/1337/versions":
+			writer.Write([]byte(`[{"id":"20","name":"1.2.0"}]`))
+		case "GET /rest/api/latest/search":
+			writer.Write([]byte(searchResponse))
+		case "GET /rest/api/latest/issue/1/comment", "GET /rest/api/latest/issue/2/comment":
+			writer.Write([]byte(`{"startAt":0,"maxResults":50,"total":0,"comments":[]}`))
+		case "PUT /rest/api/latest/issue/1":
+			writer.WriteHeader(http.StatusNoContent)
+		case "POST /rest/api/latest/issue/1/comment", "POST /rest/api/latest/issue/2/comment":
+			if failComment {
+				writer.WriteHeader(http.StatusInternalServerError)
+				writer.Write([]byte(`{"errors":{"name":"Unavailable"}}`))
+				return
+			}
+			writer.WriteHeader(http.StatusCreated)
+			writer.Write([]byte(`{"id":"100","body":"Released in 1.2.0"}`))
+		default:
+			t.Errorf("Unexpected request %v %v", req.Method, req.URL.Path)
+		}
+	})
+
+	runner, closeServer := testRunner(handler)
+	defer closeServer()
+
+	file := testFile()
+	file.Transitions = nil
+	file.Release = false
+
+	first, _ := journal.Open(dir, "1337-1.2.0")
+	runner.SetJournal(first)
+	plan, err := runner.Plan(file)
+	assert.Nil(t, err)
+	assert.EqualError(t, runner.Apply(plan), "Could not comment AB-1: Unavailable")
+
+	failComment = false
+	requests = nil
+
+	second, _ := journal.Open(dir, "1337-1.2.0")
+	runner.SetJournal(second)
+	plan, err = runner.Plan(file)
+	assert.Nil(t, err)
+	assert.Nil(t, runner.Apply(plan))
+
+	// The version was already added to AB-1, so only the comments are posted
+	assert.NotContains(t, requests, "PUT /rest/api/latest/issue/1")
+	assert.Contains(t, requests, "POST /rest/api/latest/issue/1/comment")
+	assert.Contains(t, requests, "POST /rest/api/latest/issue/2/comment")
+	assert.Len(t, second.Attempts, 2)
+	assert.Len(t, second.Entries, 3)
+}
@@ -0,0 +1,69 @@
+package release
+
+import (
+	"context"
+	"github.com/marcelblijleven/version-meister/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the runner
+const TracerName = "github.com/marcelblijleven/version-meister/release"
+
+// SetTracerProvider sets the OpenTelemetry tracer provider, the global provider is used by default
+func (r *Runner) SetTracerProvider(provider trace.TracerProvider) {
+	r.tracerProvider = provider
+}
+
+func (r *Runner) tracer() trace.Tracer {
+	if r.tracerProvider == nil {
+		return otel.GetTracerProvider().Tracer(TracerName)
+	}
+	return r.tracerProvider.Tracer(TracerName)
+}
+
+// withContext returns a copy of the runner whose client requests are made with the context
+func (r *Runner) withContext(ctx context.Context) *Runner {
+	runner := *r
+	runner.ctx = ctx
+	runner.client = api.WithContext(r.client, ctx)
+	return &runner
+}
+
+// startSpan starts a span as child of the span in the context of the runner, and returns a copy of the runner
+// whose client requests are made in the span
+func (r *Runner) startSpan(name string, attributes ...attribute.KeyValue) (*Runner, trace.Span) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := r.tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+	return r.withContext(ctx), span
+}
+
+// stepAttributes returns the span attributes of the step
+func stepAttributes(plan *Plan, step Step) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String("release.action", step.Action),
+		api.AttributeVersion.String(plan.Version.Name),
+	}
+	if step.Issue != nil {
+		attributes = append(attributes, api.AttributeIssueKey.String(step.Issue.Key))
+	}
+	if step.Transition != "" {
+		attributes = append(attributes, attribute.String("release.transition", step.Transition))
+	}
+	return attributes
+}
+
+// endSpan ends the span and marks it as failed when there is an error
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
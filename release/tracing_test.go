@@ -0,0 +1,143 @@
+package release_test
+
+import (
+	"context"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/release"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tracedRunner returns a runner for a fake JIRA with five issues that are ready for release
+func tracedRunner(provider trace.TracerProvider) (*release.Runner, *fakejira.Server) {
+	server := fakejira.New()
+	server.AddProject(1337, "AB")
+	server.SetTransitions(jira.Transition{ID: "31", Name: "Done", To: &jira.Status{Name: "Done"}})
+	for i := 0; i < 5; i++ {
+		server.AddIssue(jira.Issue{Fields: &jira.IssueFields{
+			Project: jira.Project{ID: "1337"},
+			Status:  &jira.Status{Name: "Ready for Release"},
+		}})
+	}
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	client.SetTracerProvider(provider)
+
+	runner := release.NewRunner(client, server.URL)
+	runner.SetOutput(ioutil.Discard)
+	runner.SetTracerProvider(provider)
+	return runner, server
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestRunnerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	runner, server := tracedRunner(provider)
+	defer server.Close()
+	runner.SetConcurrency(3)
+
+	ctx, root := provider.Tracer("test").Start(context.Background(), "release run")
+	plan, err := runner.PlanContext(ctx, testFile())
+	assert.Nil(t, err)
+	err = runner.ApplyContext(ctx, plan)
+	root.End()
+	assert.Nil(t, err)
+
+	spans := make(map[trace.SpanID]tracetest.SpanStub)
+	byName := make(map[string][]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.SpanContext.SpanID()] = span
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+
+	assert.Len(t, byName["release.plan"], 1)
+	assert.Len(t, byName["release.search"], 1)
+	assert.Len(t, byName["release.apply"], 1)
+	assert.Len(t, byName["release.create-version"], 1)
+	assert.Len(t, byName["release.assign-version"], 5)
+	assert.Len(t, byName["release.comment"], 5)
+	assert.Len(t, byName["release.transition"], 5)
+	assert.Len(t, byName["release.release-version"], 1)
+	assert.Equal(t, root.SpanContext().SpanID(), byName["release.apply"][0].Parent.SpanID())
+
+	search := byName["release.search"][0]
+	assert.Equal(t, `status = "Ready for Release"`, attributes(search)[api.AttributeJQL].AsString())
+
+	// The context of every assignment reaches the client, although the issues are assigned concurrently
+	keys := make(map[string]bool)
+	for _, step := range byName["release.assign-version"] {
+		assert.Equal(t, byName["release.apply"][0].SpanContext.SpanID(), step.Parent.SpanID())
+		assert.Equal(t, "1.2.0", attributes(step)[api.AttributeVersion].AsString())
+		keys[attributes(step)[api.AttributeIssueKey].AsString()] = true
+	}
+	assert.Len(t, keys, 5)
+
+	for _, operation := range byName["jira.AddVersionToIssue"] {
+		step := spans[operation.Parent.SpanID()]
+		assert.Equal(t, "release.assign-version", step.Name)
+		assert.Equal(t, attributes(step)[api.AttributeIssueKey], attributes(operation)[api.AttributeIssueKey])
+	}
+
+	for _, request := range byName["HTTP PUT"] {
+		parent := spans[request.Parent.SpanID()]
+		assert.True(t, strings.HasPrefix(parent.Name, "jira."), parent.Name)
+		assert.Equal(t, root.SpanContext().TraceID(), request.SpanContext.TraceID())
+	}
+}
+
+func TestRunnerAppliesIssuesConcurrently(t *testing.T) {
+	runner, server := tracedRunner(sdktrace.NewTracerProvider())
+	defer server.Close()
+	server.SetLatency(20 * time.Millisecond)
+	runner.SetConcurrency(5)
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+
+	start := time.Now()
+	err = runner.Apply(plan)
+
+	assert.Nil(t, err)
+	// Every issue needs 4 requests: assign, comment, get transitions and transition
+	assert.True(t, time.Since(start) < 5*4*20*time.Millisecond, time.Since(start).String())
+	for _, issue := range plan.Issues {
+		updated, _ := server.Issue(issue.Key)
+		assert.Equal(t, "Done", updated.Fields.Status.Name)
+	}
+}
+
+func TestRunnerStopsStartingIssuesAfterFailure(t *testing.T) {
+	runner, server := tracedRunner(sdktrace.NewTracerProvider())
+	defer server.Close()
+	runner.SetConcurrency(2)
+
+	plan, err := runner.Plan(testFile())
+	assert.Nil(t, err)
+	first := plan.Issues[0]
+	server.FailNext("PUT", "issue/"+first.ID, http.StatusInternalServerError)
+
+	err = runner.Apply(plan)
+
+	assert.Equal(t, "Could not assign-version "+first.Key+": AddVersion response status is 500", err.Error())
+	last, _ := server.Issue(plan.Issues[4].Key)
+	assert.Empty(t, last.Fields.FixVersions)
+}
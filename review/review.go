@@ -0,0 +1,220 @@
+package review
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrAborted is returned when the release manager quits the review
+var ErrAborted = errors.New("Review aborted")
+
+const help = `Commands:
+  1 3 5-8   toggle the issues with these numbers in or out
+  all       include all issues
+  none      exclude all issues
+  name X    change the version name to X
+  date X    change the release date to X, format 2006-01-02
+  ok        continue with the included issues
+  quit      abort without making changes
+`
+
+// Selection is the outcome of a review: the version to create and the issues to assign it to
+type Selection struct {
+	Version jira.Version
+	Issues  []jira.Issue
+}
+
+// Review lists the issues on out and reads commands from in, so the release manager can toggle issues in or out
+// and edit the version name and date. All issues are included at the start. The selection is returned after the
+// release manager confirmed it, ErrAborted is returned when they quit or in ends
+func Review(in io.Reader, out io.Writer, version jira.Version, issues []jira.Issue) (*Selection, error) {
+	reader := bufio.NewReader(in)
+	included := make([]bool, len(issues))
+	for i := range included {
+		included[i] = true
+	}
+
+	printIssues(out, version, issues, included)
+	fmt.Fprint(out, help)
+
+	for {
+		line, err := prompt(reader, out, "> ")
+		if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "all", "none":
+			for i := range included {
+				included[i] = fields[0] == "all"
+			}
+		case "name":
+			if len(fields) < 2 {
+				fmt.Fprintln(out, "Usage: name X")
+				continue
+			}
+			version.Name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+		case "date":
+			if len(fields) != 2 {
+				fmt.Fprintln(out, "Usage: date 2006-01-02")
+				continue
+			}
+			if _, err := jira.NewVersion(version.Name, false, fields[1], version.ProjectID); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			version.ReleaseDate = fields[1]
+		case "ok":
+			selection := selected(version, issues, included)
+			confirmed, err := confirm(reader, out, selection)
+			if err != nil {
+				return nil, err
+			}
+			if confirmed {
+				return selection, nil
+			}
+		case "quit", "q":
+			return nil, ErrAborted
+		case "help", "?":
+			fmt.Fprint(out, help)
+			continue
+		default:
+			if err := toggle(included, fields); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+		}
+
+		printIssues(out, version, issues, included)
+	}
+}
+
+// prompt writes the prompt and returns the next line, ErrAborted is returned when the input ends
+func prompt(reader *bufio.Reader, out io.Writer, text string) (string, error) {
+	fmt.Fprint(out, text)
+
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		fmt.Fprintln(out)
+		return "", ErrAborted
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func confirm(reader *bufio.Reader, out io.Writer, selection *Selection) (bool, error) {
+	text := fmt.Sprintf("Create version %v (%v) and assign it to %v issue(s)? [y/N] ",
+		selection.Version.Name, selection.Version.ReleaseDate, len(selection.Issues))
+
+	answer, err := prompt(reader, out, text)
+	if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// toggle flips the issues with the numbers in the fields, a field can also be a range like 5-8
+func toggle(included []bool, fields []string) error {
+	var numbers []int
+
+	for _, field := range fields {
+		bounds := strings.SplitN(field, "-", 2)
+
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return fmt.Errorf("Unknown command %v, type help for the commands", field)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return fmt.Errorf("Invalid range %v", field)
+			}
+		}
+
+		if first < 1 || last > len(included) || first > last {
+			return fmt.Errorf("Issue numbers must be between 1 and %v", len(included))
+		}
+
+		for number := first; number <= last; number++ {
+			numbers = append(numbers, number)
+		}
+	}
+
+	for _, number := range numbers {
+		included[number-1] = !included[number-1]
+	}
+
+	return nil
+}
+
+func selected(version jira.Version, issues []jira.Issue, included []bool) *Selection {
+	selection := Selection{Version: version}
+	for i, issue := range issues {
+		if included[i] {
+			selection.Issues = append(selection.Issues, issue)
+		}
+	}
+	return &selection
+}
+
+func printIssues(out io.Writer, version jira.Version, issues []jira.Issue, included []bool) {
+	count := 0
+	for _, in := range included {
+		if in {
+			count++
+		}
+	}
+
+	fmt.Fprintf(out, "\nVersion %v, release date %v, %v of %v issue(s) included\n\n",
+		version.Name, version.ReleaseDate, count, len(issues))
+
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "\tNR\tKEY\tTYPE\tSTATUS\tSUMMARY")
+	for i, issue := range issues {
+		mark := "[ ]"
+		if included[i] {
+			mark = "[x]"
+		}
+		fmt.Fprintf(table, "%v\t%v\t%v\t%v\t%v\t%v\n", mark, i+1, issue.Key, issueType(issue), status(issue), summary(issue))
+	}
+	table.Flush()
+	fmt.Fprintln(out)
+}
+
+func issueType(issue jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.IssueType == nil {
+		return ""
+	}
+	return issue.Fields.IssueType.Name
+}
+
+func status(issue jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return ""
+	}
+	return issue.Fields.Status.Name
+}
+
+func summary(issue jira.Issue) string {
+	if issue.Fields != nil && issue.Fields.Summary != "" {
+		return issue.Fields.Summary
+	}
+	return issue.Summary
+}
@@ -0,0 +1,65 @@
+package review_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/review"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func testIssues() []jira.Issue {
+	return []jira.Issue{
+		{Key: "AB-1", Fields: &jira.IssueFields{Summary: "Fix login", IssueType: &jira.IssueType{Name: "Bug"}, Status: &jira.Status{Name: "Done"}}},
+		{Key: "AB-2", Fields: &jira.IssueFields{Summary: "Add export", IssueType: &jira.IssueType{Name: "Story"}}},
+		{Key: "AB-3", Fields: &jira.IssueFields{Summary: "Update docs"}},
+		{Key: "AB-4", Fields: &jira.IssueFields{Summary: "Remove flag"}},
+	}
+}
+
+func testVersion() jira.Version {
+	return jira.Version{Name: "1.2.0", ReleaseDate: "2020-06-01", ProjectID: 1337}
+}
+
+func TestReview(t *testing.T) {
+	input := strings.NewReader("2 3-4\nname 1.2.1\ndate 2020-06-02\nok\ny\n")
+	output := new(bytes.Buffer)
+
+	selection, err := review.Review(input, output, testVersion(), testIssues())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1.2.1", selection.Version.Name)
+	assert.Equal(t, "2020-06-02", selection.Version.ReleaseDate)
+	assert.Len(t, selection.Issues, 1)
+	assert.Equal(t, "AB-1", selection.Issues[0].Key)
+	assert.Contains(t, output.String(), "[x]  1   AB-1  Bug    Done    Fix login")
+	assert.Contains(t, output.String(), "Create version 1.2.1 (2020-06-02) and assign it to 1 issue(s)? [y/N]")
+}
+
+func TestReviewDeclinedConfirmationContinues(t *testing.T) {
+	input := strings.NewReader("ok\nn\nnone\n1\nok\nyes\n")
+
+	selection, err := review.Review(input, new(bytes.Buffer), testVersion(), testIssues())
+
+	assert.Nil(t, err)
+	assert.Len(t, selection.Issues, 1)
+}
+
+func TestReviewInvalidInputIsReported(t *testing.T) {
+	input := strings.NewReader("9\ndate tomorrow\nbogus\nquit\n")
+	output := new(bytes.Buffer)
+
+	_, err := review.Review(input, output, testVersion(), testIssues())
+
+	assert.Equal(t, review.ErrAborted, err)
+	assert.Contains(t, output.String(), "Issue numbers must be between 1 and 4")
+	assert.Contains(t, output.String(), "Received incorrect date string")
+	assert.Contains(t, output.String(), "Unknown command bogus")
+}
+
+func TestReviewEndOfInputAborts(t *testing.T) {
+	_, err := review.Review(strings.NewReader("1\n"), new(bytes.Buffer), testVersion(), testIssues())
+
+	assert.Equal(t, review.ErrAborted, err)
+}
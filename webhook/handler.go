@@ -0,0 +1,318 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/jira"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SignatureHeader is the header that JIRA Cloud sends the HMAC signature of the payload in, when the webhook has
+// a secret
+const SignatureHeader = "X-Hub-Signature"
+
+// SecretParameter is the query parameter that can hold the secret instead, for JIRA versions that do not sign
+// their payloads
+const SecretParameter = "secret"
+
+// maxPayloadSize is the largest payload that is accepted
+const maxPayloadSize = 1024 * 1024
+
+// QueueSize is the number of events that can wait to be handled, requests get a 503 response when the queue is full
+const QueueSize = 100
+
+// ErrInvalidSecret is returned when a request has no valid signature or secret
+var ErrInvalidSecret = errors.New("Invalid webhook secret")
+
+// Event is the part of a JIRA webhook payload that the rules use
+type Event struct {
+	WebhookEvent string      `json:"webhookEvent"`
+	Timestamp    int64       `json:"timestamp"`
+	User         *jira.User  `json:"user,omitempty"`
+	Issue        *jira.Issue `json:"issue,omitempty"`
+	Changelog    *struct {
+		Items []jira.ChangelogItem `json:"items"`
+	} `json:"changelog,omitempty"`
+	Version *jira.Version `json:"version,omitempty"`
+}
+
+// Handler receives JIRA webhooks and assigns versions to issues with the rules. Events are queued and handled one at
+// a time, in the order they arrive
+type Handler struct {
+	client api.JiraAPI
+	secret string
+	rules  []Rule
+	out    io.Writer
+
+	queue chan Event
+	done  chan struct{}
+	mu    sync.Mutex
+}
+
+// NewHandler returns a Handler that only accepts requests with the secret
+func NewHandler(client api.JiraAPI, secret string, rules []Rule) (*Handler, error) {
+	if client == nil {
+		return nil, errors.New("Client cannot be nil")
+	}
+	if secret == "" {
+		return nil, errors.New("Secret cannot be empty")
+	}
+
+	h := &Handler{
+		client: client,
+		secret: secret,
+		rules:  rules,
+		out:    os.Stdout,
+		queue:  make(chan Event, QueueSize),
+		done:   make(chan struct{}),
+	}
+	go h.work()
+
+	return h, nil
+}
+
+// Close stops the handler after the queued events are handled. ServeHTTP must not be called after Close, so stop
+// the server first
+func (h *Handler) Close() {
+	close(h.queue)
+	<-h.done
+}
+
+// work handles the queued events until the queue is closed. Failed events are only logged, JIRA got a response
+// already and does not deliver them again
+func (h *Handler) work() {
+	defer close(h.done)
+
+	for event := range h.queue {
+		if err := h.Handle(event); err != nil {
+			fmt.Fprintln(h.out, "Could not handle", event.WebhookEvent, "event:", err)
+		}
+	}
+}
+
+// SetOutput sets the writer that progress messages are written to, os.Stdout is used by default
+func (h *Handler) SetOutput(out io.Writer) {
+	h.out = out
+}
+
+// Sign returns the value of the SignatureHeader for the payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP verifies the secret of the request and queues the event in its payload. It responds 202 without waiting
+// for the rules, JIRA stops waiting for a response after a few seconds and would deliver the event again. When the
+// queue is full it responds 503, so JIRA delivers the event later
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "Could not read payload", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err = h.verify(r, payload); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	if err = json.Unmarshal(payload, &event); err != nil {
+		http.Error(w, "Could not parse payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.queue <- event:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		fmt.Fprintln(h.out, "Queue is full, rejected", event.WebhookEvent, "event")
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Queue is full", http.StatusServiceUnavailable)
+	}
+}
+
+// verify checks the signature of the payload, or the secret query parameter when the request is not signed
+func (h *Handler) verify(r *http.Request, payload []byte) error {
+	if signature := r.Header.Get(SignatureHeader); signature != "" {
+		if hmac.Equal([]byte(signature), []byte(Sign(h.secret, payload))) {
+			return nil
+		}
+		return ErrInvalidSecret
+	}
+
+	secret := r.URL.Query().Get(SecretParameter)
+	if secret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) == 1 {
+		return nil
+	}
+	return ErrInvalidSecret
+}
+
+// Handle applies the rules that match the event. All matching rules are applied, the first error is returned
+func (h *Handler) Handle(event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var firstErr error
+	for _, rule := range h.rules {
+		if rule.Event != eventName(event.WebhookEvent) {
+			continue
+		}
+
+		var err error
+		switch rule.Event {
+		case EventIssueUpdated:
+			err = h.issueUpdated(rule, event)
+		case EventVersionReleased:
+			err = h.versionReleased(rule, event)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("Rule %v: %w", rule.Name, err)
+		}
+	}
+
+	return firstErr
+}
+
+// issueUpdated assigns the version of the rule when the issue of the event was transitioned to the status of the rule
+func (h *Handler) issueUpdated(rule Rule, event Event) error {
+	issue := event.Issue
+	if issue == nil || issue.Fields == nil || !transitionedTo(event, rule.Status) {
+		return nil
+	}
+
+	if projectID, _ := strconv.Atoi(issue.Fields.Project.ID); projectID != rule.Project {
+		return nil
+	}
+
+	if len(rule.IssueTypes) > 0 && !hasIssueType(*issue, rule.IssueTypes) {
+		return nil
+	}
+
+	version, err := h.version(rule)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(h.out, "Rule %v matched issue %v\n", rule.Name, issue.Key)
+	return h.assign(rule, *issue, *version)
+}
+
+// versionReleased assigns the version of the rule to the issues of its query, when a version of its project was
+// released. The current version is resolved after the release, so it is the next unreleased version
+func (h *Handler) versionReleased(rule Rule, event Event) error {
+	if event.Version == nil || event.Version.ProjectID != rule.Project {
+		return nil
+	}
+
+	version, err := h.version(rule)
+	if err != nil {
+		return err
+	}
+
+	issues, err := h.client.Search(rule.JQL)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(h.out, "Rule %v matched %v issue(s) after version %v was released\n", rule.Name, len(issues), event.Version.Name)
+
+	for _, issue := range issues {
+		if err = h.assign(rule, issue, *version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assign adds the version to the issue, unless the issue already has it or the rule skips issues with a version
+func (h *Handler) assign(rule Rule, issue jira.Issue, version jira.Version) error {
+	if issue.Fields != nil {
+		for _, existing := range issue.Fields.FixVersions {
+			if existing.ID == version.ID || existing.Name == version.Name {
+				return nil
+			}
+		}
+		if rule.OnlyWithoutVersion && len(issue.Fields.FixVersions) > 0 {
+			fmt.Fprintf(h.out, "Skipping issue %v, it already has a fixVersion\n", issue.Key)
+			return nil
+		}
+	}
+
+	return h.client.AddVersionToIssue(issue, version)
+}
+
+// version returns the version that the rule assigns, it is looked up for every event so releases are picked up
+func (h *Handler) version(rule Rule) (*jira.Version, error) {
+	if rule.Version != CurrentVersion {
+		version, err := h.client.FindVersion(rule.Project, rule.Version)
+		if err != nil {
+			return nil, err
+		}
+		if version == nil {
+			return nil, fmt.Errorf("Version %v does not exist in project %v", rule.Version, rule.Project)
+		}
+		return version, nil
+	}
+
+	versions, err := h.client.ProjectVersions(rule.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		if !version.Released && !version.Archived {
+			return &version, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Project %v has no unreleased version", rule.Project)
+}
+
+// transitionedTo reports if the changelog of the event has a status change to the status
+func transitionedTo(event Event, status string) bool {
+	if event.Changelog == nil {
+		return false
+	}
+
+	for _, item := range event.Changelog.Items {
+		if strings.EqualFold(item.Field, "status") && strings.EqualFold(item.ToString, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasIssueType(issue jira.Issue, issueTypes []string) bool {
+	if issue.Fields.IssueType == nil {
+		return false
+	}
+
+	for _, issueType := range issueTypes {
+		if strings.EqualFold(issue.Fields.IssueType.Name, issueType) {
+			return true
+		}
+	}
+	return false
+}
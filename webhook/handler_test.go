@@ -0,0 +1,278 @@
+package webhook_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/marcelblijleven/version-meister/api"
+	"github.com/marcelblijleven/version-meister/fakejira"
+	"github.com/marcelblijleven/version-meister/jira"
+	"github.com/marcelblijleven/version-meister/webhook"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const secret = "s3cret"
+
+const issueUpdated = `{
+	"timestamp": 1792404000000,
+	"webhookEvent": "jira:issue_updated",
+	"issue_event_type_name": "issue_generic",
+	"user": {"name": "jdoe"},
+	"issue": {
+		"id": "%v",
+		"key": "%v",
+		"fields": {
+			"project": {"id": "1337", "key": "AB"},
+			"issuetype": {"name": "Bug"},
+			"status": {"name": "Done"},
+			"fixVersions": []
+		}
+	},
+	"changelog": {
+		"id": "10400",
+		"items": [{"field": "status", "fieldtype": "jira", "from": "3", "fromString": "In Progress", "to": "10001", "toString": "%v"}]
+	}
+}`
+
+const versionReleased = `{
+	"timestamp": 1792404000000,
+	"webhookEvent": "jira:version_released",
+	"version": {"id": "%v", "name": "1.1.0", "released": true, "releaseDate": "2026-10-19", "projectId": 1337}
+}`
+
+// testServer returns a fake JIRA with a released, an archived and two unreleased versions, and an issue in progress
+func testServer() (*fakejira.Server, jira.Issue) {
+	server := fakejira.New()
+	server.AddProject(1337, "AB")
+	server.AddVersion(jira.Version{Name: "1.0.0", ProjectID: 1337, Released: true})
+	server.AddVersion(jira.Version{Name: "1.0.1", ProjectID: 1337, Archived: true})
+	server.AddVersion(jira.Version{Name: "1.1.0", ProjectID: 1337})
+	server.AddVersion(jira.Version{Name: "1.2.0", ProjectID: 1337})
+	issue := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{
+		Project:   jira.Project{ID: "1337"},
+		IssueType: &jira.IssueType{Name: "Bug"},
+		Status:    &jira.Status{Name: "Done"},
+	}})
+	return server, issue
+}
+
+func testHandler(t *testing.T, server *fakejira.Server, rules string) *webhook.Handler {
+	parsed, err := webhook.ParseRules([]byte(rules))
+	assert.Nil(t, err)
+
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+
+	handler, err := webhook.NewHandler(client, secret, parsed)
+	assert.Nil(t, err)
+	handler.SetOutput(ioutil.Discard)
+	return handler
+}
+
+func post(handler http.Handler, target, payload string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", target, bytes.NewBufferString(payload))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func signed(payload string) http.Header {
+	return http.Header{webhook.SignatureHeader: {webhook.Sign(secret, []byte(payload))}}
+}
+
+func fixVersions(server *fakejira.Server, key string) []string {
+	issue, _ := server.Issue(key)
+	var names []string
+	for _, version := range issue.Fields.FixVersions {
+		names = append(names, version.Name)
+	}
+	return names
+}
+
+func TestIssueTransitionedToDoneGetsCurrentVersion(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	handler := testHandler(t, server, "rules: [{name: done, project: 1337, status: Done}]")
+
+	payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "Done")
+	resp := post(handler, "/webhook", payload, signed(payload))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	// Delivering the event again does not change the issue
+	resp = post(handler, "/webhook", payload, signed(payload))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	handler.Close()
+	assert.Equal(t, []string{"1.1.0"}, fixVersions(server, issue.Key))
+}
+
+func TestIssueUpdatesThatDoNotMatchAreIgnored(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+
+	tests := map[string]string{
+		"other status":     "rules: [{project: 1337, status: Closed}]",
+		"other project":    "rules: [{project: 42, status: Done}]",
+		"other issue type": "rules: [{project: 1337, status: Done, issue_types: [Story]}]",
+		"other event":      "rules: [{project: 1337, event: version_released, jql: project = AB}]",
+	}
+
+	for name, rules := range tests {
+		payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "Done")
+		handler := testHandler(t, server, rules)
+		resp := post(handler, "/webhook", payload, signed(payload))
+		handler.Close()
+
+		assert.Equal(t, http.StatusAccepted, resp.Code, name)
+		assert.Empty(t, fixVersions(server, issue.Key), name)
+	}
+
+	// An update of an issue that is already done, without a status change, is ignored as well
+	handler := testHandler(t, server, "rules: [{project: 1337, status: Done}]")
+	payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "In Review")
+	assert.Equal(t, http.StatusAccepted, post(handler, "/webhook", payload, signed(payload)).Code)
+	handler.Close()
+	assert.Empty(t, fixVersions(server, issue.Key))
+	assert.Empty(t, server.Requests())
+}
+
+func TestVersionReleasedAssignsNextVersion(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	handler := testHandler(t, server, `
+rules:
+  - name: carry over
+    event: version_released
+    project: 1337
+    jql: project = AB AND status = Done AND fixVersion IS EMPTY
+`)
+
+	// JIRA sends the event after the version is released
+	released := server.Versions(1337)[2]
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	assert.Nil(t, client.ReleaseVersion(released))
+
+	payload := fmt.Sprintf(versionReleased, released.ID)
+	resp := post(handler, "/webhook?secret="+secret, payload, nil)
+	handler.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, []string{"1.2.0"}, fixVersions(server, issue.Key))
+}
+
+func TestNamedVersionAndOnlyWithoutVersion(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	assigned := server.AddIssue(jira.Issue{Fields: &jira.IssueFields{
+		Project:     jira.Project{ID: "1337"},
+		FixVersions: []jira.Version{server.Versions(1337)[0]},
+	}})
+	handler := testHandler(t, server, `
+rules:
+  - event: version_released
+    project: 1337
+    jql: project = AB
+    version: 1.2.0
+    only_without_version: true
+`)
+
+	payload := fmt.Sprintf(versionReleased, "1")
+	resp := post(handler, "/webhook", payload, signed(payload))
+	handler.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, []string{"1.2.0"}, fixVersions(server, issue.Key))
+	assert.Equal(t, []string{"1.0.0"}, fixVersions(server, assigned.Key))
+}
+
+func TestFailedRuleIsLogged(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	handler := testHandler(t, server, "rules: [{name: done, project: 1337, status: Done, version: 9.9.9}]")
+	var log bytes.Buffer
+	handler.SetOutput(&log)
+
+	payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "Done")
+	resp := post(handler, "/webhook", payload, signed(payload))
+	handler.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "Could not handle jira:issue_updated event: Rule done: Version 9.9.9 does not exist in project 1337\n", log.String())
+}
+
+// blockingClient blocks looking up versions until it is released, so events stay in the queue
+type blockingClient struct {
+	api.JiraAPI
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingClient) ProjectVersions(projectID int) ([]jira.Version, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.JiraAPI.ProjectVersions(projectID)
+}
+
+func TestFullQueueRespondsServiceUnavailable(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	parsed, _ := webhook.ParseRules([]byte("rules: [{project: 1337, status: Done}]"))
+	client := server.Client()
+	client.SetOutput(ioutil.Discard)
+	blocking := &blockingClient{JiraAPI: client, started: make(chan struct{}, webhook.QueueSize+1), release: make(chan struct{})}
+	handler, _ := webhook.NewHandler(blocking, secret, parsed)
+	handler.SetOutput(ioutil.Discard)
+	payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "Done")
+
+	// The first event is being handled, the others wait in the queue
+	assert.Equal(t, http.StatusAccepted, post(handler, "/webhook", payload, signed(payload)).Code)
+	<-blocking.started
+	for i := 0; i < webhook.QueueSize; i++ {
+		assert.Equal(t, http.StatusAccepted, post(handler, "/webhook", payload, signed(payload)).Code)
+	}
+
+	resp := post(handler, "/webhook", payload, signed(payload))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+
+	close(blocking.release)
+	handler.Close()
+	assert.Equal(t, []string{"1.1.0"}, fixVersions(server, issue.Key))
+}
+
+func TestInvalidRequests(t *testing.T) {
+	server, issue := testServer()
+	defer server.Close()
+	handler := testHandler(t, server, "rules: [{project: 1337, status: Done}]")
+	payload := fmt.Sprintf(issueUpdated, issue.ID, issue.Key, "Done")
+
+	assert.Equal(t, http.StatusUnauthorized, post(handler, "/webhook", payload, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, post(handler, "/webhook?secret=wrong", payload, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, post(handler, "/webhook", payload, http.Header{webhook.SignatureHeader: {webhook.Sign("wrong", []byte(payload))}}).Code)
+	// The signature covers the payload
+	assert.Equal(t, http.StatusUnauthorized, post(handler, "/webhook", payload+" ", signed(payload)).Code)
+	assert.Equal(t, http.StatusBadRequest, post(handler, "/webhook", "{", signed("{")).Code)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/webhook", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	handler.Close()
+	assert.Empty(t, server.Requests())
+}
+
+func TestNewHandlerRequiresSecret(t *testing.T) {
+	server := fakejira.New()
+	defer server.Close()
+
+	_, err := webhook.NewHandler(server.Client(), "", nil)
+
+	assert.EqualError(t, err, "Secret cannot be empty")
+}
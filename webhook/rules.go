@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"strings"
+)
+
+// Events that rules can apply to, the jira: prefix of the webhook event names is optional
+const (
+	EventIssueUpdated    = "issue_updated"
+	EventVersionReleased = "version_released"
+)
+
+// CurrentVersion selects the first unreleased version of the project, in the order of the project versions in JIRA
+const CurrentVersion = "current"
+
+// RulesFile holds the rules of the webhook server, as read from a webhook.yaml file
+type RulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule assigns a version to the issues of an event.
+//
+// For issue_updated events the rule matches issues of the project that were transitioned to Status, optionally
+// limited to IssueTypes. For version_released events of the project the rule assigns the issues that JQL matches,
+// e.g. the issues that are done but were not released
+type Rule struct {
+	Name       string   `yaml:"name"`
+	Event      string   `yaml:"event"`
+	Project    int      `yaml:"project"`
+	Status     string   `yaml:"status"`
+	IssueTypes []string `yaml:"issue_types"`
+	JQL        string   `yaml:"jql"`
+	// Version is the name of the version to assign, or CurrentVersion which is the default
+	Version string `yaml:"version"`
+	// OnlyWithoutVersion skips issues that already have a fixVersion
+	OnlyWithoutVersion bool `yaml:"only_without_version"`
+}
+
+// ParseRules returns the rules in the content of a rules file
+func ParseRules(content []byte) ([]Rule, error) {
+	var file RulesFile
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, err
+	}
+
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("At least one rule is required")
+	}
+
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %v", i+1)
+		}
+		if rule.Event == "" {
+			rule.Event = EventIssueUpdated
+		}
+		rule.Event = eventName(rule.Event)
+		if rule.Version == "" {
+			rule.Version = CurrentVersion
+		}
+
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return file.Rules, nil
+}
+
+// LoadRules reads and parses the rules file at path, see ParseRules
+func LoadRules(path string) ([]Rule, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := ParseRules(content)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read rules file %v: %v", path, err)
+	}
+
+	return rules, nil
+}
+
+func (r Rule) validate() error {
+	if r.Project == 0 {
+		return fmt.Errorf("Rule %v has no project", r.Name)
+	}
+
+	switch r.Event {
+	case EventIssueUpdated:
+		if r.Status == "" {
+			return fmt.Errorf("Rule %v has no status", r.Name)
+		}
+		if r.JQL != "" {
+			return fmt.Errorf("Rule %v has a jql, which is only used for %v events", r.Name, EventVersionReleased)
+		}
+	case EventVersionReleased:
+		if r.JQL == "" {
+			return fmt.Errorf("Rule %v has no jql", r.Name)
+		}
+		if r.Status != "" || len(r.IssueTypes) > 0 {
+			return fmt.Errorf("Rule %v has a status or issue types, which are only used for %v events", r.Name, EventIssueUpdated)
+		}
+	default:
+		return fmt.Errorf("Rule %v has unknown event %v, expected %v or %v", r.Name, r.Event, EventIssueUpdated, EventVersionReleased)
+	}
+
+	return nil
+}
+
+// eventName returns the event without the jira: prefix that webhook payloads use
+func eventName(event string) string {
+	return strings.TrimPrefix(event, "jira:")
+}
@@ -0,0 +1,68 @@
+package webhook_test
+
+import (
+	"github.com/marcelblijleven/version-meister/webhook"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const rulesFile = `
+rules:
+  - name: done
+    project: 1337
+    status: Done
+    issue_types: [Bug, Story]
+  - event: jira:version_released
+    project: 1337
+    jql: project = 1337 AND status = Done AND fixVersion IS EMPTY
+    version: Backlog
+    only_without_version: true
+`
+
+func TestParseRules(t *testing.T) {
+	rules, err := webhook.ParseRules([]byte(rulesFile))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []webhook.Rule{
+		{
+			Name:       "done",
+			Event:      webhook.EventIssueUpdated,
+			Project:    1337,
+			Status:     "Done",
+			IssueTypes: []string{"Bug", "Story"},
+			Version:    webhook.CurrentVersion,
+		},
+		{
+			Name:               "rule 2",
+			Event:              webhook.EventVersionReleased,
+			Project:            1337,
+			JQL:                "project = 1337 AND status = Done AND fixVersion IS EMPTY",
+			Version:            "Backlog",
+			OnlyWithoutVersion: true,
+		},
+	}, rules)
+}
+
+func TestParseRulesReturnsErrors(t *testing.T) {
+	tests := map[string]string{
+		"rules: []":                                                            "At least one rule is required",
+		"rules: [{status: Done}]":                                              "Rule rule 1 has no project",
+		"rules: [{name: done, project: 1}]":                                    "Rule done has no status",
+		"rules: [{project: 1, status: Done, jql: x}]":                          "Rule rule 1 has a jql, which is only used for version_released events",
+		"rules: [{project: 1, event: version_released}]":                       "Rule rule 1 has no jql",
+		"rules: [{project: 1, event: issue_created}]":                          "Rule rule 1 has unknown event issue_created, expected issue_updated or version_released",
+		"rules: [{project: 1, status: Done, unknown: 1}]":                      "yaml: unmarshal errors:\n  line 1: field unknown not found in type webhook.Rule",
+		"rules: [{project: 1, event: version_released, jql: x, status: Done}]": "Rule rule 1 has a status or issue types, which are only used for issue_updated events",
+	}
+
+	for content, expected := range tests {
+		_, err := webhook.ParseRules([]byte(content))
+		assert.EqualError(t, err, expected, content)
+	}
+}
+
+func TestLoadRulesMissingFile(t *testing.T) {
+	_, err := webhook.LoadRules("does-not-exist.yaml")
+
+	assert.NotNil(t, err)
+}